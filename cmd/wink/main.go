@@ -1,12 +1,15 @@
 package main
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 
 	"github.com/fatih/color"
 	"github.com/harnyk/wink/internal/app"
 	"github.com/harnyk/wink/internal/auth"
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/localbackend"
 )
 
 // this will be replaced in the goreleaser build
@@ -27,9 +30,17 @@ func main() {
 	if err != nil {
 		exitWithError(err)
 	}
-	authPrompt := auth.NewAuthPrompt(fname)
+	settings, err := loadSettings()
+	if err != nil {
+		exitWithError(err)
+	}
 
-	a := app.NewApp(authPrompt, app.Version(version), app.ConfigFileName(fname))
+	authPrompt := auth.NewAuthPrompt(fname, settings.Config.Auth.PasswordCommand)
+	if settings.Config.Backend == config.BackendLocal {
+		authPrompt = auth.NewLocalAuth(localbackend.EmployeeID)
+	}
+
+	a := app.NewApp(authPrompt, app.Version(version), app.ConfigFileName(fname), settings)
 
 	err = a.Run()
 	if err != nil {
@@ -43,9 +54,27 @@ func exitWithError(err error) {
 }
 
 func getConfigFileName() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := config.HomeDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".wink", "secrets"), nil
+	return filepath.Join(dir, "secrets"), nil
+}
+
+func loadSettings() (app.Settings, error) {
+	userFile, err := config.UserFile()
+	if err != nil {
+		return app.Settings{}, err
+	}
+
+	cfg, files, err := config.Load(userFile)
+	if errors.Is(err, config.ErrInvalid) {
+		// the config commands still run, to fix the setting with
+		return app.Settings{Config: cfg, Files: files, Invalid: err}, nil
+	}
+	if err != nil {
+		return app.Settings{}, err
+	}
+
+	return app.Settings{Config: cfg, Files: files}, nil
 }
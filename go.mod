@@ -2,21 +2,25 @@ module github.com/harnyk/wink
 
 go 1.20
 
-require golang.org/x/term v0.7.0
+require (
+	github.com/beevik/ntp v1.0.0
+	github.com/fatih/color v1.14.1
+	github.com/spf13/cobra v1.6.1
+	golang.org/x/term v0.7.0
+)
 
 require (
-	github.com/beevik/ntp v1.0.0 // indirect
-	github.com/fatih/color v1.14.1 // indirect
 	github.com/inconshreveable/mousetrap v1.0.1 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.17 // indirect
-	github.com/spf13/cobra v1.6.1 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 )
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/docopt/docopt-go v0.0.0-20180111231733-ee0de3bc6815
 	github.com/go-resty/resty/v2 v2.7.0
+	github.com/gofrs/flock v0.8.1
 	github.com/jbenet/go-simple-encrypt v0.0.0-20180707112328-087dc59b773e
 	github.com/jinzhu/now v1.1.5
 	golang.org/x/net v0.9.0 // indirect
@@ -5,15 +5,20 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/harnyk/wink/internal/auditlog"
 	"github.com/harnyk/wink/internal/auth"
+	"github.com/harnyk/wink/internal/config"
 	"github.com/harnyk/wink/internal/cryptostore"
 	"github.com/harnyk/wink/internal/easteregg"
 	"github.com/harnyk/wink/internal/entities"
+	"github.com/harnyk/wink/internal/hooks"
 	"github.com/harnyk/wink/internal/peopleapi"
 	"github.com/harnyk/wink/internal/report"
+	"github.com/harnyk/wink/internal/status"
 	"github.com/harnyk/wink/internal/timecheck"
 	"github.com/harnyk/wink/internal/ui"
 	"github.com/jinzhu/now"
@@ -21,7 +26,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
-const clockTolerance = time.Duration(10 * time.Minute)
+const (
+	fetchWorkers    = 4
+	fetchRetries    = 3
+	fetchRetryDelay = time.Duration(500 * time.Millisecond)
+)
+
+// verifyTolerance is the difference to PeopleHR totals that is still
+// considered a match, it covers the rounding of seconds
+const verifyTolerance = time.Duration(1 * time.Minute)
 
 type App interface {
 	Run() error
@@ -31,17 +44,28 @@ type app struct {
 	authPrompt     auth.AuthPrompt
 	version        Version
 	configFileName ConfigFileName
+	settings       Settings
+	// clockOffset is how far the system clock is ahead of NTP time, nil if unknown
+	clockOffset *time.Duration
+	// dryRun shows the writes instead of sending them, set by --dry-run
+	dryRun bool
+	// trace logs the PeopleHR requests and responses, set by --trace
+	trace bool
+	// noOvernight keeps a check-out to today's timesheet, set by out --no-overnight
+	noOvernight bool
 }
 
 func NewApp(
 	authPrompt auth.AuthPrompt,
 	appVersion Version,
 	configFileName ConfigFileName,
+	settings Settings,
 ) App {
 	return &app{
 		authPrompt:     authPrompt,
 		version:        appVersion,
 		configFileName: configFileName,
+		settings:       settings,
 	}
 }
 
@@ -62,6 +86,18 @@ func (a *app) Run() error {
 		},
 	}
 	rootCmd.Flags().BoolP("version", "v", false, "Print the version number of wink")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Show the writes instead of sending them")
+	rootCmd.PersistentFlags().Bool("trace", false, "Log every PeopleHR request and response to stderr, secrets redacted")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		a.dryRun = cmd.Flag("dry-run").Value.String() == "true"
+		a.trace = cmd.Flag("trace").Value.String() == "true"
+
+		// an invalid configuration leaves only the commands to fix it with
+		if a.settings.Invalid != nil && cmd != rootCmd && !isConfigCommand(cmd) {
+			return fmt.Errorf("%w. Set it again with `wink config set`", a.settings.Invalid)
+		}
+		return nil
+	}
 
 	lsCmd := &cobra.Command{
 		Use:     "ls",
@@ -69,9 +105,15 @@ func (a *app) Run() error {
 		Short:   "List all my check-ins",
 		Long:    "List all my check-ins",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return a.doList()
+			mode, err := cacheModeFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			return a.doList(mode)
 		},
 	}
+	addCacheFlags(lsCmd)
 
 	inCmd := &cobra.Command{
 		Use:     "in [time]",
@@ -102,11 +144,14 @@ func (a *app) Run() error {
 				timeArg = args[0]
 			}
 
+			a.noOvernight = cmd.Flag("no-overnight").Value.String() == "true"
+
 			a.warnAboutMisconfiguredSystemClock()
 
 			return a.doCheckInOut(timeArg, peopleapi.ActionTypeOut)
 		},
 	}
+	outCmd.Flags().Bool("no-overnight", false, "Don't close a shift left open yesterday")
 
 	initCmd := &cobra.Command{
 		Use:   "init",
@@ -123,36 +168,47 @@ func (a *app) Run() error {
 		Short:   "Generate a report",
 		Long:    "Generate a report",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var start time.Time
-			var end time.Time
-			var err error
+			start, end, err := a.parseDateRange(cmd)
+			if err != nil {
+				return err
+			}
 
-			if cmd.Flag("start").Value.String() == "" {
-				start = now.BeginningOfMonth()
-			} else {
-				start, err = time.Parse("2006-01-02", cmd.Flag("start").Value.String())
-				if err != nil {
-					return err
-				}
+			opts := reportOptions{
+				output:   cmd.Flag("output").Value.String(),
+				template: cmd.Flag("template").Value.String(),
+				detailed: cmd.Flag("detailed").Value.String() == "true",
+				verify:   cmd.Flag("verify").Value.String() == "true",
 			}
 
-			if cmd.Flag("end").Value.String() == "" {
-				end = time.Now()
-			} else {
-				end, err = time.Parse("2006-01-02", cmd.Flag("end").Value.String())
-				if err != nil {
-					return err
-				}
+			if cmd.Flag("by-project").Value.String() == "true" {
+				return a.doProjectReport(start, end, opts)
+			}
+
+			preset := cmd.Flag("compliance").Value.String()
+			if preset == "" {
+				preset = a.settings.Config.Report.Compliance
+			}
+			opts.compliance, err = a.complianceRules(preset)
+			if err != nil {
+				return err
 			}
 
-			jsonFile := cmd.Flag("output").Value.String()
+			opts.cacheMode, err = cacheModeFromFlags(cmd)
+			if err != nil {
+				return err
+			}
 
-			return a.doReport(start, end, jsonFile)
+			return a.doReport(start, end, opts)
 		},
 	}
-	reportCmd.Flags().StringP("start", "s", "", "Start date, format: 2006-01-02")
-	reportCmd.Flags().StringP("end", "e", "", "End date, format: 2006-01-02")
-	reportCmd.Flags().StringP("output", "o", "", "Output JSON file")
+	addDateRangeFlags(reportCmd)
+	reportCmd.Flags().StringP("output", "o", "", "Output file (JSON, unless --template is given)")
+	reportCmd.Flags().StringP("template", "t", "", "Render the report through a text/template file (.html/.htm files use html/template)")
+	reportCmd.Flags().BoolP("detailed", "d", false, "List work intervals, breaks and raw slots of every day")
+	reportCmd.Flags().StringP("compliance", "c", "", "Check working-time rules using a preset: de, eu, uk, or none (default: report.compliance)")
+	reportCmd.Flags().Bool("verify", false, "Compare computed hours with the totals reported by PeopleHR")
+	reportCmd.Flags().Bool("by-project", false, "Sum the project time entries by project and task instead")
+	addCacheFlags(reportCmd)
 
 	versionCmd := &cobra.Command{
 		Use:     "version",
@@ -174,13 +230,445 @@ func (a *app) Run() error {
 		},
 	}
 
-	rootCmd.AddCommand(lsCmd, inCmd, outCmd, initCmd, reportCmd, versionCmd, keyCmd)
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local timesheet cache",
+		Long:  "Manage the local timesheet cache",
+	}
+
+	cacheStatsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show what is in the cache",
+		Long:  "Show what is in the cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.doCacheStats()
+		},
+	}
+
+	cacheClearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Delete the cache",
+		Long:  "Delete the cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.doCacheClear()
+		},
+	}
+
+	cacheRefreshCmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Fetch the timesheets of a range into the cache",
+		Long:  "Fetch the timesheets of a range into the cache, ignoring the cached ones",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, end, err := a.parseDateRange(cmd)
+			if err != nil {
+				return err
+			}
+
+			return a.doCacheRefresh(start, end)
+		},
+	}
+	addDateRangeFlags(cacheRefreshCmd)
+
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd, cacheRefreshCmd)
+
+	logCmd := &cobra.Command{
+		Use:   "log",
+		Short: "Show the local log of every write wink sent",
+		Long:  "Show the local log of every write wink sent to PeopleHR",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := auditlog.Filter{
+				Action: cmd.Flag("action").Value.String(),
+				Slot:   cmd.Flag("slot").Value.String(),
+			}
+
+			var err error
+			if since := cmd.Flag("since").Value.String(); since != "" {
+				filter.Since, err = time.Parse("2006-01-02", since)
+				if err != nil {
+					return err
+				}
+			}
+			if until := cmd.Flag("until").Value.String(); until != "" {
+				filter.Until, err = time.Parse("2006-01-02", until)
+				if err != nil {
+					return err
+				}
+			}
+
+			return a.doLog(filter, cmd.Flag("json").Value.String() == "true")
+		},
+	}
+	logCmd.Flags().String("since", "", "Only entries for timesheet dates from, format: 2006-01-02")
+	logCmd.Flags().String("until", "", "Only entries for timesheet dates until, format: 2006-01-02")
+	logCmd.Flags().String("action", "", "Only entries of the action: CreateNewTimesheet, UpdateTimesheet")
+	logCmd.Flags().String("slot", "", "Only entries of the slot, e.g. TimeOut3")
+	logCmd.Flags().Bool("json", false, "Print the entries as JSON")
+
+	reconcileCmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Compare the audit log with the timesheets on the server",
+		Long:  "Compare what wink submitted with what PeopleHR currently returns, and list the slots changed, removed or added outside wink",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, end, err := a.parseDateRange(cmd)
+			if err != nil {
+				return err
+			}
+
+			return a.doReconcile(start, end, cmd.Flag("json").Value.String() == "true")
+		},
+	}
+	addDateRangeFlags(reconcileCmd)
+	reconcileCmd.Flags().Bool("json", false, "Print the discrepancies as JSON")
+
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show or change the configuration",
+		Long:  "Show or change the non-secret configuration of wink",
+	}
+
+	configGetCmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a configuration value",
+		Long:  "Print a configuration value",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.doConfigGet(args[0])
+		},
+	}
+
+	configSetCmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a configuration value",
+		Long:  "Change a configuration value in the user config file",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.doConfigSet(args[0], args[1])
+		},
+	}
+
+	configListCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "Print all configuration values",
+		Long:    "Print all configuration values",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.doConfigList()
+		},
+	}
+
+	configCmd.AddCommand(configGetCmd, configSetCmd, configListCmd)
+
+	remindCmd := &cobra.Command{
+		Use:   "remind",
+		Short: "Remind about a forgotten check-out or a missing check-in",
+		Long:  "Check today's timesheet against the remind.* settings and notify about a forgotten check-out, a missing check-in or a crossed daily maximum",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.doRemind(cmd.Flag("daemon").Value.String() == "true")
+		},
+	}
+	remindCmd.Flags().Bool("daemon", false, "Keep running and check every remind.interval")
+
+	installTimersCmd := &cobra.Command{
+		Use:   "install-timers",
+		Short: "Install systemd user timers for reminders and the automatic check-out",
+		Long:  "Write and enable systemd user units running `wink remind` every remind.interval and `wink out` at timers.auto_checkout",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.doInstallTimers()
+		},
+	}
+
+	uninstallTimersCmd := &cobra.Command{
+		Use:   "uninstall-timers",
+		Short: "Remove the systemd user timers installed by wink",
+		Long:  "Remove the systemd user timers installed by wink",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.doUninstallTimers()
+		},
+	}
+
+	promptCmd := &cobra.Command{
+		Use:   "prompt",
+		Short: "Print a compact check-in status for a shell prompt or a status bar",
+		Long:  "Print a compact check-in status from the local cache, never waiting for the network. An expired cache is refreshed in the background.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.doPrompt(cmd.Flag("format").Value.String(), cmd.Flag("output").Value.String())
+		},
+	}
+	promptCmd.Flags().StringP("format", "f", "", "text/template format, fields: .Icon .State .In .Total .Since .Stale (default: prompt.format)")
+	promptCmd.Flags().StringP("output", "o", status.OutputPlain, "Output: plain, waybar, i3blocks or tmux")
+
+	projectCmd := &cobra.Command{
+		Use:   "project",
+		Short: "Log and list project time entries",
+		Long:  "Log and list project time entries",
+	}
+
+	projectLogCmd := &cobra.Command{
+		Use:   "log <project> <task> <duration>",
+		Short: "Log time spent on a project task",
+		Long:  "Log time spent on a project task, the duration is e.g. 1h30m, 1:30 or 1.5",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			date := time.Now()
+			if dateFlag := cmd.Flag("date").Value.String(); dateFlag != "" {
+				var err error
+				date, err = time.Parse(a.settings.Config.Report.DateFormat, dateFlag)
+				if err != nil {
+					return err
+				}
+			}
+
+			return a.doProjectLog(args[0], args[1], args[2], date, cmd.Flag("notes").Value.String())
+		},
+	}
+	projectLogCmd.Flags().String("date", "", "Date of the entry, format: 2006-01-02 (or report.date_format), default: today")
+	projectLogCmd.Flags().String("notes", "", "Notes of the entry")
+
+	projectListCmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List project time entries",
+		Long:    "List project time entries",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, end, err := a.parseDateRange(cmd)
+			if err != nil {
+				return err
+			}
+
+			return a.doProjectList(start, end, cmd.Flag("json").Value.String() == "true")
+		},
+	}
+	addDateRangeFlags(projectListCmd)
+	projectListCmd.Flags().Bool("json", false, "Print the entries as JSON")
+
+	projectCmd.AddCommand(projectLogCmd, projectListCmd)
+
+	holidayCmd := &cobra.Command{
+		Use:   "holiday",
+		Short: "Show and request holidays",
+		Long:  "Show and request holidays",
+	}
+
+	holidayBalanceCmd := &cobra.Command{
+		Use:   "balance",
+		Short: "Show the holiday days taken and remaining this holiday year",
+		Long:  "Show the holiday days taken, pending and remaining this holiday year",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.doHolidayBalance()
+		},
+	}
+
+	holidayListCmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List holidays",
+		Long:    "List holidays, of the current holiday year by default",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, end, err := a.parseHolidayRange(cmd)
+			if err != nil {
+				return err
+			}
+
+			return a.doHolidayList(start, end, cmd.Flag("json").Value.String() == "true")
+		},
+	}
+	addDateRangeFlags(holidayListCmd)
+	holidayListCmd.Flags().Bool("json", false, "Print the holidays as JSON")
+
+	holidayRequestCmd := &cobra.Command{
+		Use:   "request",
+		Short: "Request a holiday",
+		Long:  "Request a holiday, the number of days is counted over remind.workdays",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dateFormat := a.settings.Config.Report.DateFormat
+
+			from, err := time.Parse(dateFormat, cmd.Flag("from").Value.String())
+			if err != nil {
+				return err
+			}
+
+			to := from
+			if toFlag := cmd.Flag("to").Value.String(); toFlag != "" {
+				to, err = time.Parse(dateFormat, toFlag)
+				if err != nil {
+					return err
+				}
+			}
+
+			var partOfTheDay string
+			switch halfDay := cmd.Flag("half-day").Value.String(); halfDay {
+			case "":
+			case "am", "AM":
+				partOfTheDay = peopleapi.PartOfDayAM
+			case "pm", "PM":
+				partOfTheDay = peopleapi.PartOfDayPM
+			default:
+				return fmt.Errorf("--half-day must be am or pm")
+			}
+
+			return a.doHolidayRequest(from, to, partOfTheDay, cmd.Flag("comments").Value.String())
+		},
+	}
+	holidayRequestCmd.Flags().String("from", "", "First day, format: 2006-01-02 (or report.date_format)")
+	holidayRequestCmd.Flags().String("to", "", "Last day, format: 2006-01-02 (or report.date_format), default: --from")
+	holidayRequestCmd.Flags().String("half-day", "", "Request half a day: am or pm")
+	holidayRequestCmd.Flags().Lookup("half-day").NoOptDefVal = "am"
+	holidayRequestCmd.Flags().String("comments", "", "Comments for the approver")
+	holidayRequestCmd.MarkFlagRequired("from")
+
+	holidayCmd.AddCommand(holidayBalanceCmd, holidayListCmd, holidayRequestCmd)
+
+	absenceCmd := &cobra.Command{
+		Use:   "absence",
+		Short: "Show absences",
+		Long:  "Show absences",
+	}
+
+	absenceListCmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List absences",
+		Long:    "List absences, of the current holiday year by default",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, end, err := a.parseHolidayRange(cmd)
+			if err != nil {
+				return err
+			}
+
+			return a.doAbsenceList(start, end, cmd.Flag("json").Value.String() == "true")
+		},
+	}
+	addDateRangeFlags(absenceListCmd)
+	absenceListCmd.Flags().Bool("json", false, "Print the absences as JSON")
+
+	absenceCmd.AddCommand(absenceListCmd)
+
+	whoamiCmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Show the employee details of the configured account",
+		Long:  "Show the name, department, location, manager and contracted hours of the configured employee",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.doWhoami()
+		},
+	}
+
+	teamCmd := &cobra.Command{
+		Use:   "team",
+		Short: "Reports on a team, needs an admin API key",
+		Long:  "Reports on a team, needs an admin API key",
+	}
+
+	teamReportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report the totals, missing check-outs and invalid sequences of a team",
+		Long:  "Fetch the timesheets of every employee of the team concurrently and report the totals, missing check-outs and invalid sequences per person",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, end, err := a.parseDateRange(cmd)
+			if err != nil {
+				return err
+			}
+
+			employeeIDs, err := a.teamEmployees(cmd.Flag("employees").Value.String(), cmd.Flag("team").Value.String())
+			if err != nil {
+				return err
+			}
+
+			return a.doTeamReport(start, end, employeeIDs, cmd.Flag("format").Value.String(), cmd.Flag("output").Value.String())
+		},
+	}
+	addDateRangeFlags(teamReportCmd)
+	teamReportCmd.Flags().String("employees", "", "Comma-separated employee IDs")
+	teamReportCmd.Flags().String("team", "", "Name of a team of the teams setting")
+	teamReportCmd.Flags().StringP("format", "f", teamFormatText, "Format: text, csv or json")
+	teamReportCmd.Flags().StringP("output", "o", "", "Output file, default: print")
+
+	teamCmd.AddCommand(teamReportCmd)
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the timesheets of a range",
+		Long:  "Export the timesheets of a range as JSON lines, the format of the local backend file, or as CSV with one row per work interval",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, end, err := a.parseDateRange(cmd)
+			if err != nil {
+				return err
+			}
+
+			mode, err := cacheModeFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			return a.doExport(start, end, cmd.Flag("format").Value.String(), cmd.Flag("output").Value.String(), mode)
+		},
+	}
+	addDateRangeFlags(exportCmd)
+	exportCmd.Flags().StringP("format", "f", exportFormatJSONLines, "Format: jsonl or csv")
+	exportCmd.Flags().StringP("output", "o", "", "Output file, default: print")
+	addCacheFlags(exportCmd)
+
+	rootCmd.AddCommand(lsCmd, inCmd, outCmd, initCmd, reportCmd, versionCmd, keyCmd, cacheCmd, logCmd, reconcileCmd, configCmd, remindCmd, installTimersCmd, uninstallTimersCmd, promptCmd, projectCmd, holidayCmd, absenceCmd, whoamiCmd, teamCmd, exportCmd)
 
 	return rootCmd.Execute()
 }
 
+func addDateRangeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("start", "s", "", "Start date, format: 2006-01-02 (or report.date_format)")
+	cmd.Flags().StringP("end", "e", "", "End date, format: 2006-01-02 (or report.date_format)")
+}
+
+// parseDateRange reads the --start and --end flags,
+// the range defaults to report.default_range up to now
+func (a *app) parseDateRange(cmd *cobra.Command) (time.Time, time.Time, error) {
+	var start time.Time
+	var end time.Time
+	var err error
+
+	dateFormat := a.settings.Config.Report.DateFormat
+
+	start, end = defaultDateRange(a.settings.Config.Report.DefaultRange, time.Now())
+
+	if cmd.Flag("start").Value.String() != "" {
+		start, err = time.Parse(dateFormat, cmd.Flag("start").Value.String())
+		if err != nil {
+			return start, end, err
+		}
+	}
+
+	if cmd.Flag("end").Value.String() != "" {
+		end, err = time.Parse(dateFormat, cmd.Flag("end").Value.String())
+		if err != nil {
+			return start, end, err
+		}
+	}
+
+	return start, end, nil
+}
+
+func defaultDateRange(name string, t time.Time) (time.Time, time.Time) {
+	n := now.With(t)
+
+	switch name {
+	case config.RangeWeek:
+		return n.Monday(), t
+	case config.RangeLastWeek:
+		lastWeek := now.With(n.Monday().AddDate(0, 0, -7))
+		return lastWeek.BeginningOfDay(), lastWeek.EndOfSunday()
+	case config.RangeLastMonth:
+		lastMonth := now.With(n.BeginningOfMonth().AddDate(0, -1, 0))
+		return lastMonth.BeginningOfMonth(), lastMonth.EndOfMonth()
+	}
+
+	return n.BeginningOfMonth(), t
+}
+
 func (a *app) warnAboutMisconfiguredSystemClock() {
-	diff, err := timecheck.GetTimeDifference()
+	// the local backend works offline, and only trusts the system clock anyway
+	if a.isLocalBackend() {
+		return
+	}
+
+	diff, err := timecheck.GetTimeDifference(a.settings.Config.NTPServer)
 	if err != nil {
 		fmt.Println(color.YellowString("WARNING: Could not get NTP time difference"))
 		fmt.Println(color.YellowString("I don't know if your system clock is OK."))
@@ -191,6 +679,10 @@ func (a *app) warnAboutMisconfiguredSystemClock() {
 		return
 	}
 
+	a.clockOffset = &diff
+
+	clockTolerance := time.Duration(a.settings.Config.ClockTolerance)
+
 	if diff > clockTolerance || diff < -clockTolerance {
 		fmt.Println(color.YellowString("▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓"))
 		fmt.Println(color.YellowString("▓                                          ▓"))
@@ -235,20 +727,30 @@ func (a *app) doCheckInOut(timeFlag string, action peopleapi.ActionType) error {
 		return err
 	}
 
-	if err = checkInOut(au, action, checkInTime); err != nil {
+	if err = a.checkInOut(au, action, checkInTime); err != nil {
 		return err
 	}
 
+	if !a.dryRun {
+		a.invalidateToday(au)
+	}
+
 	switch action {
 	case peopleapi.ActionTypeIn:
 		{
+			if a.dryRun {
+				return nil
+			}
 			printSuccess(fmt.Sprintf("Checked in at %s", checkInTime.Format("15:04")))
-			fmt.Println(easteregg.GetRandomCheckinPhrase(0.5))
+			fmt.Println(easteregg.GetRandomCheckinPhrase(a.settings.Config.EasterEgg.RudeProbability))
 		}
 	case peopleapi.ActionTypeOut:
 		{
+			if a.dryRun {
+				return nil
+			}
 			printSuccess(fmt.Sprintf("Checked out at %s", checkInTime.Format("15:04")))
-			fmt.Println(easteregg.GetRandomCheckoutPhrase(0.5))
+			fmt.Println(easteregg.GetRandomCheckoutPhrase(a.settings.Config.EasterEgg.RudeProbability))
 		}
 	}
 
@@ -256,16 +758,15 @@ func (a *app) doCheckInOut(timeFlag string, action peopleapi.ActionType) error {
 
 }
 
-func (a *app) doList() error {
+func (a *app) doList(mode cacheMode) error {
 	authData, err := a.authPrompt.Get()
 	if err != nil {
 		return err
 	}
 
-	client := peopleapi.NewClient(authData)
-
 	// Get my check-ins
-	checkInResult, err := client.GetTimesheet(time.Time{}, time.Time{})
+	today := time.Now()
+	timeSheets, err := a.loadTimesheets(authData, today, today, mode)
 	if err != nil {
 		return err
 
@@ -273,12 +774,12 @@ func (a *app) doList() error {
 
 	fmt.Println()
 
-	if len(checkInResult.Result) == 0 {
+	if len(timeSheets) == 0 {
 		return fmt.Errorf("no check-ins found")
 	}
 
 	// Print my check-ins
-	for _, timeSheet := range checkInResult.Result {
+	for _, timeSheet := range timeSheets {
 		fmt.Println(timeSheet.TimesheetDate)
 		actions := peopleapi.TimeSheetToActionsList(&timeSheet)
 		for _, action := range actions {
@@ -290,15 +791,14 @@ func (a *app) doList() error {
 }
 
 func (a *app) doInit() error {
+	if a.isLocalBackend() {
+		fmt.Printf("The %s backend needs no credentials, timesheets are kept in %s\n", config.BackendLocal, a.localFileName())
+		return nil
+	}
 
 	u := ui.NewUI()
 
-	apiKey, err := u.AskString("Please enter your API key:")
-	if err != nil {
-		return err
-	}
-
-	employeeID, err := u.AskString("Please enter your employee ID:")
+	authData, employee, err := a.askCredentials(u)
 	if err != nil {
 		return err
 	}
@@ -311,8 +811,8 @@ func (a *app) doInit() error {
 	store := cryptostore.NewCryptoStore[entities.Secrets](string(a.configFileName))
 
 	err = store.Store(entities.Secrets{
-		APIKey:     apiKey,
-		EmployeeID: employeeID,
+		APIKey:     authData.APIKey,
+		EmployeeID: authData.EmployeeID,
 	}, string(password))
 
 	if err != nil {
@@ -333,54 +833,131 @@ func (a *app) doInit() error {
 	fmt.Printf("Your API key is: %s...\n", loadedRecord.APIKey[:maxAPIKeyLength])
 	fmt.Printf("Your employee ID is: %s\n", loadedRecord.EmployeeID)
 
+	if employee != nil {
+		a.seedDailyTarget(employee)
+	}
+
 	printSuccess("Successfully initialized wink")
 
 	return nil
 
 }
 
-func (a *app) doReport(timeStart, timeEnd time.Time, jsonFile string) error {
+type reportOptions struct {
+	output     string
+	template   string
+	detailed   bool
+	verify     bool
+	compliance *report.ComplianceRules
+	cacheMode  cacheMode
+	// holidays are the dates of approved holidays, set by doReport
+	holidays map[string]bool
+}
+
+func (a *app) renderOptions(o reportOptions) report.RenderOptions {
+	return report.RenderOptions{
+		Detailed:    o.detailed,
+		Compliance:  o.compliance,
+		DateFormat:  a.settings.Config.Report.DisplayDateFormat,
+		DayFormat:   a.settings.Config.Report.DayFormat,
+		Holidays:    o.holidays,
+		DailyTarget: time.Duration(a.settings.Config.Report.DailyTarget),
+	}
+}
+
+func (a *app) doReport(timeStart, timeEnd time.Time, opts reportOptions) error {
 	authData, err := a.authPrompt.Get()
 	if err != nil {
 		return err
 	}
 
-	client := peopleapi.NewClient(authData)
-
-	reportData, err := client.GetTimesheet(timeStart, timeEnd)
+	timeSheets, err := a.loadTimesheets(authData, timeStart, timeEnd, opts.cacheMode)
 	if err != nil {
 		return err
 	}
 
-	if jsonFile != "" {
-		jsonStr, err := report.RenderDailyReportJSON(timeStart, timeEnd, reportData.Result)
-		if err != nil {
-			return err
+	if a.settings.Config.Report.Holidays {
+		opts.holidays = a.holidayDates(authData, timeStart, timeEnd, opts.cacheMode)
+	}
+
+	if opts.verify {
+		results := report.VerifyTotals(timeStart, timeEnd, timeSheets)
+
+		if opts.output != "" {
+			jsonStr, err := report.RenderVerifyReportJSON(results, verifyTolerance)
+			if err != nil {
+				return err
+			}
+
+			return writeReportFile(opts.output, jsonStr)
 		}
 
-		err = os.MkdirAll(filepath.Dir(jsonFile), 0755)
+		fmt.Println()
+		fmt.Println(report.RenderVerifyReport(timeStart, timeEnd, results, verifyTolerance, a.renderOptions(opts)))
+		return nil
+	}
+
+	if opts.template != "" {
+		rendered, err := report.RenderTemplateReport(timeStart, timeEnd, timeSheets, opts.template)
 		if err != nil {
 			return err
 		}
 
-		err = ioutil.WriteFile(jsonFile, []byte(jsonStr), 0644)
+		if opts.output == "" {
+			fmt.Print(string(rendered))
+			return nil
+		}
+
+		return writeReportFile(opts.output, rendered)
+	}
+
+	if opts.output != "" {
+		jsonStr, err := report.RenderDailyReportJSON(timeStart, timeEnd, timeSheets, a.renderOptions(opts))
 		if err != nil {
 			return err
 		}
 
-		printSuccess(fmt.Sprintf("Report written to %s", jsonFile))
-		return nil
+		return writeReportFile(opts.output, jsonStr)
 	}
 
 	fmt.Println()
 
-	reportStr := report.RenderDailyReport(timeStart, timeEnd, reportData.Result)
+	reportStr := report.RenderDailyReport(timeStart, timeEnd, timeSheets, a.renderOptions(opts))
 
 	fmt.Println(reportStr)
 
 	return nil
 }
 
+// fetchTimesheets fetches a long range in concurrent monthly chunks,
+// showing the progress on the terminal
+func fetchTimesheets(client peopleapi.Client, timeStart, timeEnd time.Time) ([]peopleapi.TimeSheet, error) {
+	progress := ui.NewProgress("Fetching timesheets")
+	defer progress.Done()
+
+	return peopleapi.FetchTimesheetRange(client, timeStart, timeEnd, peopleapi.FetchOptions{
+		Workers:    fetchWorkers,
+		Retries:    fetchRetries,
+		RetryDelay: fetchRetryDelay,
+		Progress:   progress.Update,
+	})
+}
+
+func writeReportFile(fileName string, data []byte) error {
+	err := os.MkdirAll(filepath.Dir(fileName), 0755)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(fileName, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Report written to %s", fileName))
+	return nil
+}
+
 func (a *app) doVersion() error {
 	fmt.Println(a.version)
 	return nil
@@ -392,57 +969,173 @@ func printSuccess(message string) {
 	color.Green("▓▓▓▓ " + message + " ▓▓▓▓")
 }
 
-func checkInOut(authData peopleapi.Auth, action peopleapi.ActionType, checkInTime time.Time) error {
+func (a *app) checkInOut(authData peopleapi.Auth, action peopleapi.ActionType, checkInTime time.Time) error {
+	unlock, err := a.lockTimesheet()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	timeStr := checkInTime.Format("15:04")
+	return a.writeCheckInOut(a.newClient(authData), authData, action, checkInTime)
+}
 
-	client := peopleapi.NewClient(authData)
+// writeCheckInOut writes the check-in or check-out to the next free slot of today's timesheet
+// and reads it back. The caller holds the timesheet lock.
+func (a *app) writeCheckInOut(client peopleapi.Client, authData peopleapi.Auth, action peopleapi.ActionType, checkInTime time.Time) error {
+	timeStr := checkInTime.Format("15:04")
 
-	timeSheetResult, err := client.GetTimesheet(time.Time{}, time.Time{})
+	currentTimesheet, slot, overnight, err := a.nextSlot(client, authData, action, true)
 	if err != nil {
 		return err
 	}
-	currentTimesheet := peopleapi.TimeSheet{}
-	if len(timeSheetResult.Result) > 0 {
-		currentTimesheet = timeSheetResult.Result[0]
+	if overnight {
+		return a.checkOutOvernight(client, authData, currentTimesheet, checkInTime)
+	}
+
+	fmt.Printf("Checking %s\n", strings.ToLower(string(action)))
+
+	hookContext := a.newHookContext(&currentTimesheet, action, checkInTime, slot)
+
+	if err := a.runHooks(hooks.PhasePre, hookContext); err != nil {
+		return err
+	}
+
+	// a pre hook may have changed the timesheet, e.g. by running wink itself
+	if a.hooksEnabled() && len(a.hookCommands(hooks.PhasePre, hookContext.Action)) > 0 {
+		_, slot, overnight, err = a.nextSlot(client, authData, action, false)
+		if err != nil {
+			return err
+		}
+		if overnight {
+			return errCantCheckOut
+		}
+		hookContext.Slot = slot
+	}
+
+	for attempt := 1; ; attempt++ {
+		if slot == "TimeIn1" {
+			// create a new timesheet
+			resp, err := client.CreateNewTimesheet(timeStr)
+			a.recordWrite(authData, auditlog.Entry{Action: peopleapi.ActionCreateNewTimesheet, Slot: slot, Time: timeStr}, resp, err)
+			if err != nil {
+				return err
+			}
+		} else {
+			resp, err := client.CheckInOut(slot, timeStr)
+			a.recordWrite(authData, auditlog.Entry{Action: peopleapi.ActionUpdateTimesheet, Slot: slot, Time: timeStr}, resp, err)
+			if err != nil {
+				return err
+			}
+		}
+
+		if a.dryRun {
+			break
+		}
+
+		written, err := verifyWrite(client, slot)
+		if err != nil {
+			fmt.Println(color.YellowString("WARNING: Could not verify the write: %s", err))
+			break
+		}
+		if peopleapi.SameClock(written, timeStr) {
+			break
+		}
+
+		if attempt == writeAttempts {
+			return fmt.Errorf("%w: %s holds %s instead of %s, it keeps being overwritten by another write. "+
+				"Check it with `wink ls` and fix it in %s", errLostUpdate, slot, displaySlot(written), timeStr, a.timesheetEditor())
+		}
+
+		fmt.Println(color.YellowString("WARNING: %s holds %s instead of %s, another write got there first. Retrying with the next free slot", slot, displaySlot(written), timeStr))
+
+		// the other write may have checked in or out already, nextSlot checks again
+		_, slot, overnight, err = a.nextSlot(client, authData, action, false)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errLostUpdate, err)
+		}
+		if overnight {
+			return fmt.Errorf("%w: %w", errLostUpdate, errCantCheckOut)
+		}
+		hookContext.Slot = slot
+	}
+
+	if err := a.runHooks(hooks.PhasePost, hookContext); err != nil {
+		fmt.Println(color.YellowString("WARNING: %s", err))
 	}
 
-	actions := peopleapi.TimeSheetToActionsList(&currentTimesheet)
+	return nil
+}
+
+// nextSlot reads today's timesheet and returns it along with the slot the action goes to,
+// compacting a full timesheet first. overnight is set for a check-out with nothing today,
+// it may end a shift started yesterday. warn shows the warning about an invalid timesheet.
+func (a *app) nextSlot(client peopleapi.Client, authData peopleapi.Auth, action peopleapi.ActionType, warn bool) (timeSheet peopleapi.TimeSheet, slot string, overnight bool, err error) {
+	timeSheet, err = readToday(client)
+	if err != nil {
+		return timeSheet, "", false, err
+	}
+
+	day, parseErr := peopleapi.ParseDay(timeSheet)
+	isOpen, slot := day.IsOpen(), day.NextSlot()
+	if parseErr != nil {
+		// a timesheet edited by hand mustn't block, go by its last entry as wink always did
+		isOpen, slot = afterLastEntry(timeSheet)
+		if warn {
+			fmt.Println(color.YellowString("WARNING: Today's timesheet is invalid, %s. "+
+				"Fix it in %s, `wink ls` lists its slots. Going by its last entry for now", parseErr, a.timesheetEditor()))
+		}
+	}
 
 	switch action {
 	case peopleapi.ActionTypeIn:
 		{
-			if !peopleapi.CanCheckIn(actions) {
-				return fmt.Errorf("you can't check in")
+			if isOpen {
+				return timeSheet, "", false, fmt.Errorf("you can't check in")
 			}
-			fmt.Println("Checking in")
 		}
 	case peopleapi.ActionTypeOut:
 		{
-			if !peopleapi.CanCheckOut(actions) {
-				return fmt.Errorf("you can't check out")
+			if !isOpen {
+				// nothing today yet, it may be the end of a shift started yesterday
+				if parseErr == nil && len(day.Intervals) == 0 && !a.noOvernight {
+					return timeSheet, "", true, nil
+				}
+				return timeSheet, "", false, errCantCheckOut
 			}
-			fmt.Println("Checking out")
 		}
 	}
 
-	slot := peopleapi.GetNextSlotName(currentTimesheet)
-	if slot == "" {
-		return fmt.Errorf("timesheet is full")
+	if slot == "" && parseErr != nil {
+		return timeSheet, "", false, fmt.Errorf("%w, and it can't be compacted while it is invalid", errTimesheetFull)
 	}
-
-	if slot == "TimeIn1" {
-		// create a new timesheet
-		err := client.CreateNewTimesheet(timeStr)
+	if slot == "" {
+		day, err = a.compactFullDay(client, authData, timeSheet, day)
 		if err != nil {
-			return err
+			return timeSheet, "", false, err
 		}
-	} else {
-		err = client.CheckInOut(slot, timeStr)
-		if err != nil {
-			return err
+		if timeSheet, err = day.TimeSheet(); err != nil {
+			return timeSheet, "", false, err
 		}
+		slot = day.NextSlot()
 	}
 
-	return nil
+	return timeSheet, slot, false, nil
+}
+
+// afterLastEntry tells whether the timesheet is checked in and which slot comes next
+// from its last filled slot alone, for a timesheet ParseDay can't read
+func afterLastEntry(timeSheet peopleapi.TimeSheet) (isOpen bool, slot string) {
+	actions := peopleapi.TimeSheetToActionsList(&timeSheet)
+	if len(actions) == 0 {
+		return false, peopleapi.SlotNames[0]
+	}
+
+	last := actions[len(actions)-1]
+	isOpen = last.Type == peopleapi.ActionTypeIn
+
+	next := peopleapi.SlotIndex(last.Slot) + 1
+	if next >= len(peopleapi.SlotNames) {
+		return isOpen, ""
+	}
+	return isOpen, peopleapi.SlotNames[next]
 }
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+// newTestApp returns an app on the local backend with its files in a temporary directory
+func newTestApp(t *testing.T) *app {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Backend = config.BackendLocal
+
+	return &app{
+		configFileName: ConfigFileName(filepath.Join(t.TempDir(), "secrets")),
+		settings:       Settings{Config: cfg},
+	}
+}
+
+// writeTimesheets replaces the timesheets of the local backend
+func writeTimesheets(t *testing.T, a *app, timeSheets ...peopleapi.TimeSheet) {
+	t.Helper()
+
+	var data []byte
+	for _, timeSheet := range timeSheets {
+		line, err := json.Marshal(timeSheet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	if err := os.WriteFile(a.localFileName(), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readTimesheet returns the timesheet of the date from the local backend, empty if there is none
+func readTimesheet(t *testing.T, a *app, date time.Time) peopleapi.TimeSheet {
+	t.Helper()
+
+	resp, err := a.newClient(peopleapi.Auth{}).GetTimesheet(date, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Result) == 0 {
+		return peopleapi.TimeSheet{}
+	}
+	return resp.Result[0]
+}
+
+func TestAfterLastEntry(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeSheet peopleapi.TimeSheet
+		wantOpen  bool
+		wantSlot  string
+	}{
+		{name: "empty", wantSlot: "TimeIn1"},
+		{name: "check-in after an open interval", timeSheet: peopleapi.TimeSheet{TimeIn1: "08:00", TimeIn2: "09:00"}, wantOpen: true, wantSlot: "TimeOut2"},
+		{name: "check-out without check-in", timeSheet: peopleapi.TimeSheet{TimeIn1: "08:00", TimeOut2: "09:00"}, wantSlot: "TimeIn3"},
+		{name: "unreadable time", timeSheet: peopleapi.TimeSheet{TimeIn1: "soon"}, wantOpen: true, wantSlot: "TimeOut1"},
+		{name: "last slot", timeSheet: peopleapi.TimeSheet{TimeIn1: "08:00", TimeOut15: "09:00"}, wantSlot: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isOpen, slot := afterLastEntry(tt.timeSheet)
+			if isOpen != tt.wantOpen || slot != tt.wantSlot {
+				t.Errorf("afterLastEntry() = %v, %q, want %v, %q", isOpen, slot, tt.wantOpen, tt.wantSlot)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/localbackend"
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+func (a *app) isLocalBackend() bool {
+	return a.settings.Config.Backend == config.BackendLocal
+}
+
+func (a *app) localFileName() string {
+	if a.settings.Config.LocalFile != "" {
+		return a.settings.Config.LocalFile
+	}
+	return filepath.Join(filepath.Dir(string(a.configFileName)), "timesheets.jsonl")
+}
+
+// timesheetEditor names where timesheets can be fixed by hand
+func (a *app) timesheetEditor() string {
+	if a.isLocalBackend() {
+		return a.localFileName()
+	}
+	return "the PeopleHR web UI"
+}
+
+// newClient returns the client of the configured backend
+func (a *app) newClient(authData peopleapi.Auth) peopleapi.Client {
+	if a.isLocalBackend() {
+		var opts []localbackend.Option
+		if a.dryRun {
+			opts = append(opts, localbackend.WithDryRun(os.Stdout))
+		}
+		return localbackend.NewClient(a.localFileName(), opts...)
+	}
+	return peopleapi.NewClient(authData, a.peopleHROptions()...)
+}
+
+// peopleHROptions are the PeopleHR client options of the --dry-run and --trace flags
+func (a *app) peopleHROptions() []peopleapi.Option {
+	var opts []peopleapi.Option
+	if a.dryRun {
+		opts = append(opts, peopleapi.WithDryRun(os.Stdout))
+	}
+	if a.trace {
+		opts = append(opts, peopleapi.WithTrace(os.Stderr))
+	}
+	return opts
+}
+
+// requirePeopleHR fails the commands which only work with PeopleHR
+func (a *app) requirePeopleHR(command string) error {
+	if a.isLocalBackend() {
+		return fmt.Errorf("%s needs the %s backend: %w", command, config.BackendPeopleHR, peopleapi.ErrNotSupported)
+	}
+	return nil
+}
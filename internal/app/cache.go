@@ -0,0 +1,201 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/tscache"
+	"github.com/spf13/cobra"
+)
+
+type cacheMode int
+
+const (
+	// cacheModeDefault serves fresh entries from the cache and fetches the rest
+	cacheModeDefault cacheMode = iota
+	// cacheModeOffline serves everything from the cache, never hitting the network
+	cacheModeOffline
+	// cacheModeRefresh ignores the cache and re-fetches everything
+	cacheModeRefresh
+)
+
+func addCacheFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("offline", false, "Use cached timesheets only, don't hit the network")
+	cmd.Flags().Bool("refresh", false, "Ignore cached timesheets and fetch them again")
+}
+
+func cacheModeFromFlags(cmd *cobra.Command) (cacheMode, error) {
+	offline := cmd.Flag("offline").Value.String() == "true"
+	refresh := cmd.Flag("refresh").Value.String() == "true"
+
+	switch {
+	case offline && refresh:
+		return cacheModeDefault, fmt.Errorf("--offline and --refresh can't be used together")
+	case offline:
+		return cacheModeOffline, nil
+	case refresh:
+		return cacheModeRefresh, nil
+	}
+
+	return cacheModeDefault, nil
+}
+
+func (a *app) cacheFileName() string {
+	return filepath.Join(filepath.Dir(string(a.configFileName)), "cache")
+}
+
+func (a *app) openCache(authData peopleapi.Auth) (tscache.Cache, error) {
+	password, err := a.authPrompt.Password()
+	if err != nil {
+		return nil, err
+	}
+
+	return tscache.NewCache(a.cacheFileName(), password, authData.EmployeeID), nil
+}
+
+// loadTimesheets returns the timesheets of the range, taking fresh ones
+// from the local cache and fetching only the missing or expired dates
+func (a *app) loadTimesheets(authData peopleapi.Auth, timeStart, timeEnd time.Time, mode cacheMode) ([]peopleapi.TimeSheet, error) {
+	// the local backend is a file already, there is nothing to cache
+	if a.isLocalBackend() {
+		resp, err := a.newClient(authData).GetTimesheet(timeStart, timeEnd)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Result, nil
+	}
+
+	cache, err := a.openCache(authData)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	fetchStart, fetchEnd := timeStart, timeEnd
+
+	if mode != cacheModeRefresh {
+		cached, missing := cache.Lookup(timeStart, timeEnd, now, mode == cacheModeOffline)
+
+		if mode == cacheModeOffline {
+			if len(missing) > 0 {
+				fmt.Println(color.YellowString("%d day(s) of the range are not cached", len(missing)))
+			}
+			return cached, nil
+		}
+
+		if len(missing) == 0 {
+			return cached, nil
+		}
+
+		fetchStart, fetchEnd = missing[0], missing[len(missing)-1]
+	}
+
+	client := a.newClient(authData)
+
+	fetched, err := fetchTimesheets(client, fetchStart, fetchEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	if today := now.Format("2006-01-02"); fetchStart.Format("2006-01-02") <= today && today <= fetchEnd.Format("2006-01-02") {
+		a.savePromptSnapshot(fetched, now)
+	}
+
+	if err := cache.Store(fetchStart, fetchEnd, fetched, now); err != nil {
+		fmt.Println(color.YellowString("WARNING: Could not update the cache: %s", err))
+		return fetched, nil
+	}
+
+	timeSheets, _ := cache.Lookup(timeStart, timeEnd, now, true)
+
+	return timeSheets, nil
+}
+
+// invalidateToday drops today's cached timesheet after it was changed
+func (a *app) invalidateToday(authData peopleapi.Auth) {
+	a.invalidateDay(authData, time.Now())
+}
+
+// invalidateDay drops the cached timesheet of the date after a write to it
+func (a *app) invalidateDay(authData peopleapi.Auth, date time.Time) {
+	if a.isLocalBackend() {
+		return
+	}
+
+	if date.Format("2006-01-02") == time.Now().Format("2006-01-02") {
+		a.expirePromptSnapshot()
+	}
+
+	cache, err := a.openCache(authData)
+	if err != nil {
+		return
+	}
+
+	if err := cache.Invalidate(date); err != nil {
+		fmt.Println(color.YellowString("WARNING: Could not update the cache: %s", err))
+	}
+}
+
+func (a *app) doCacheStats() error {
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return err
+	}
+
+	cache, err := a.openCache(authData)
+	if err != nil {
+		return err
+	}
+
+	stats, err := cache.Stats(time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("File       : %s\n", stats.FileName)
+	fmt.Printf("Size       : %d bytes\n", stats.FileSize)
+	fmt.Printf("Dates      : %d\n", stats.Dates)
+	fmt.Printf("Timesheets : %d\n", stats.TimeSheets)
+	fmt.Printf("Expired    : %d\n", stats.Stale)
+	if stats.Dates > 0 {
+		fmt.Printf("Range      : %s - %s\n", stats.Oldest.Format("2006-01-02"), stats.Newest.Format("2006-01-02"))
+	}
+
+	return nil
+}
+
+func (a *app) doCacheRefresh(start time.Time, end time.Time) error {
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return err
+	}
+
+	timeSheets, err := a.loadTimesheets(authData, start, end, cacheModeRefresh)
+	if err != nil {
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("%d timesheet(s) refreshed", len(timeSheets)))
+
+	return nil
+}
+
+func (a *app) doCacheClear() error {
+	// clearing doesn't need the password, so the key doesn't matter here
+	cache := tscache.NewCache(a.cacheFileName(), "", "")
+
+	if err := cache.Clear(); err != nil {
+		return err
+	}
+	if err := os.Remove(a.promptFileName()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	printSuccess("Cache cleared")
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/harnyk/wink/internal/auditlog"
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/ui"
+)
+
+var errTimesheetFull = errors.New("timesheet is full")
+
+type slotChange struct {
+	slot string
+	from string
+	to   string
+}
+
+// compactFullDay makes room in today's full timesheet as configured by the compact settings.
+// The compacted slots are written back before the compacted day is returned.
+func (a *app) compactFullDay(client peopleapi.Client, authData peopleapi.Auth, current peopleapi.TimeSheet, day peopleapi.Day) (peopleapi.Day, error) {
+	settings := a.settings.Config.Compact
+	if settings.Mode == config.CompactOff {
+		return day, errTimesheetFull
+	}
+
+	maxBreak := time.Duration(settings.MaxBreak)
+
+	compacted := day.Compact(maxBreak)
+	if len(compacted.Intervals) == len(day.Intervals) || len(compacted.Intervals) == 0 {
+		return day, fmt.Errorf("%w, and it has no breaks shorter than %s to merge", errTimesheetFull, maxBreak)
+	}
+
+	timeSheet, err := compacted.TimeSheet()
+	if err != nil {
+		return day, err
+	}
+	timeSheet.TimesheetDate = current.TimesheetDate
+
+	changes := slotChanges(current, timeSheet)
+
+	fmt.Printf("The timesheet is full, compacting it by merging breaks shorter than %s:\n", maxBreak)
+	for _, change := range changes {
+		fmt.Printf("  %-10s %s -> %s\n", change.slot, displaySlot(change.from), displaySlot(change.to))
+	}
+
+	if settings.Mode == config.CompactPrompt {
+		confirmed, err := ui.NewUI().Confirm("Write the compacted timesheet?")
+		if err != nil {
+			return day, err
+		}
+		if !confirmed {
+			return day, errTimesheetFull
+		}
+	}
+
+	resp, err := client.UpdateTimesheet(timeSheet)
+	for _, change := range changes {
+		a.recordWrite(authData, auditlog.Entry{
+			Action: peopleapi.ActionUpdateTimesheet,
+			Date:   timeSheet.TimesheetDate,
+			Slot:   change.slot,
+			Time:   change.to,
+		}, resp, err)
+	}
+	if err != nil {
+		return day, fmt.Errorf("could not write the compacted timesheet: %w", err)
+	}
+
+	// clearing slots isn't a usual write, make sure PeopleHR took it as sent
+	if !a.dryRun {
+		written, err := readToday(client)
+		if err != nil {
+			return day, fmt.Errorf("could not read the compacted timesheet back: %w", err)
+		}
+		for _, slot := range peopleapi.SlotNames {
+			if !peopleapi.SameClock(written.Slot(slot), timeSheet.Slot(slot)) {
+				return day, fmt.Errorf("the compacted timesheet wasn't stored as sent, %s holds %s instead of %s, please check it in the PeopleHR web UI",
+					slot, displaySlot(written.Slot(slot)), displaySlot(timeSheet.Slot(slot)))
+			}
+		}
+	}
+
+	fmt.Println(color.GreenString("Compacted %d intervals into %d", len(day.Intervals), len(compacted.Intervals)))
+
+	return compacted, nil
+}
+
+// slotChanges lists the slots whose values differ, in slot order
+func slotChanges(from peopleapi.TimeSheet, to peopleapi.TimeSheet) []slotChange {
+	var changes []slotChange
+	for _, slot := range peopleapi.SlotNames {
+		if from.Slot(slot) != to.Slot(slot) {
+			changes = append(changes, slotChange{slot: slot, from: from.Slot(slot), to: to.Slot(slot)})
+		}
+	}
+	return changes
+}
+
+func displaySlot(value string) string {
+	if value == "" {
+		return "(empty)"
+	}
+	return value
+}
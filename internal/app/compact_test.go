@@ -0,0 +1,94 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+// keepingClearedSlots stores only the filled slots of an update, the way a server
+// that doesn't clear slots sent empty would
+type keepingClearedSlots struct {
+	peopleapi.Client
+}
+
+func (c keepingClearedSlots) UpdateTimesheet(timeSheet peopleapi.TimeSheet) (*peopleapi.EditResponse, error) {
+	date, _ := time.Parse("2006-01-02", timeSheet.TimesheetDate)
+
+	resp, err := c.GetTimesheet(date, date)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := resp.Result[0]
+	for _, slot := range peopleapi.SlotNames {
+		if value := timeSheet.Slot(slot); value != "" {
+			stored.SetSlot(slot, value)
+		}
+	}
+
+	return c.Client.UpdateTimesheet(stored)
+}
+
+func TestCompactFullDay(t *testing.T) {
+	today := time.Now()
+
+	// 15 intervals of 28 minutes, 2 minutes apart
+	full := peopleapi.TimeSheet{TimesheetDate: today.Format("2006-01-02")}
+	for pair := 1; pair <= peopleapi.SlotCount; pair++ {
+		in := 8*time.Hour + time.Duration(pair-1)*30*time.Minute
+		interval := peopleapi.Interval{Pair: pair}
+		full.SetSlot(interval.InSlot(), peopleapi.FormatClock(in))
+		full.SetSlot(interval.OutSlot(), peopleapi.FormatClock(in+28*time.Minute))
+	}
+
+	tests := []struct {
+		name    string
+		client  func(peopleapi.Client) peopleapi.Client
+		wantErr string
+	}{
+		{
+			name:   "written back",
+			client: func(c peopleapi.Client) peopleapi.Client { return c },
+		},
+		{
+			name:    "cleared slots not stored",
+			client:  func(c peopleapi.Client) peopleapi.Client { return keepingClearedSlots{c} },
+			wantErr: "TimeIn2 holds 08:30:00 instead of (empty)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t)
+			a.settings.Config.Compact.Mode = config.CompactAuto
+			writeTimesheets(t, a, full)
+
+			day, err := peopleapi.ParseDay(full)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			compacted, err := a.compactFullDay(tt.client(a.newClient(peopleapi.Auth{})), peopleapi.Auth{}, full, day)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("compactFullDay() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := []peopleapi.Interval{{Pair: 1, In: 8 * time.Hour, Out: 15*time.Hour + 28*time.Minute}}
+			if len(compacted.Intervals) != 1 || compacted.Intervals[0] != want[0] {
+				t.Errorf("compactFullDay() = %v, want %v", compacted.Intervals, want)
+			}
+			if got := readTimesheet(t, a, today); got.TimeOut1 != "15:28:00" || got.TimeIn2 != "" {
+				t.Errorf("stored timesheet = %+v, want one interval", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"time"
+
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/report"
+)
+
+// complianceRules returns the rules of the preset with report.compliance_rules applied on top,
+// nil for none. Without a preset the rules come from report.compliance_rules alone.
+func (a *app) complianceRules(preset string) (*report.ComplianceRules, error) {
+	overrides := a.settings.Config.Report.ComplianceRules
+
+	if preset == "none" || (preset == "" && !overrides.IsSet()) {
+		return nil, nil
+	}
+
+	rules := report.ComplianceRules{Name: "custom"}
+	if preset != "" {
+		var err error
+		if rules, err = report.GetCompliancePreset(preset); err != nil {
+			return nil, err
+		}
+		if overrides.IsSet() {
+			rules.Name += ", customized"
+		}
+	}
+
+	if len(overrides.Breaks) > 0 {
+		rules.Breaks = nil
+		for _, step := range overrides.Breaks {
+			after, minBreak, err := config.BreakStep(step)
+			if err != nil {
+				return nil, err
+			}
+			rules.Breaks = append(rules.Breaks, report.BreakRule{After: after, MinBreak: minBreak})
+		}
+	}
+
+	for _, override := range []struct {
+		value     string
+		threshold *time.Duration
+	}{
+		{overrides.MinBreakLength, &rules.MinBreakLength},
+		{overrides.MaxDaily, &rules.MaxDaily},
+		{overrides.MinRest, &rules.MinRest},
+	} {
+		if override.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(override.value)
+		if err != nil {
+			return nil, err
+		}
+		*override.threshold = d
+	}
+
+	return &rules, nil
+}
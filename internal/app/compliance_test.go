@@ -0,0 +1,62 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/report"
+)
+
+func TestComplianceRules(t *testing.T) {
+	de := report.CompliancePresets["de"]
+
+	tests := []struct {
+		name      string
+		preset    string
+		overrides config.ComplianceRulesConfig
+		want      *report.ComplianceRules
+		wantErr   bool
+	}{
+		{name: "no preset", want: nil},
+		{name: "none", preset: "none", overrides: config.ComplianceRulesConfig{MinRest: "12h"}, want: nil},
+		{name: "preset", preset: "de", want: &de},
+		{
+			name:      "preset with overrides",
+			preset:    "de",
+			overrides: config.ComplianceRulesConfig{Breaks: []string{"5h:15m"}, MaxDaily: "0s", MinRest: "12h"},
+			want: &report.ComplianceRules{
+				Name:           "de, customized",
+				Breaks:         []report.BreakRule{{After: 5 * time.Hour, MinBreak: 15 * time.Minute}},
+				MinBreakLength: de.MinBreakLength,
+				MinRest:        12 * time.Hour,
+			},
+		},
+		{
+			name:      "overrides alone",
+			overrides: config.ComplianceRulesConfig{MaxDaily: "10h"},
+			want:      &report.ComplianceRules{Name: "custom", MaxDaily: 10 * time.Hour},
+		},
+		{name: "unknown preset", preset: "fr", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t)
+			a.settings.Config.Report.ComplianceRules = tt.overrides
+
+			got, err := a.complianceRules(tt.preset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("complianceRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("complianceRules() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// the preset itself is left as it is
+	if !reflect.DeepEqual(report.CompliancePresets["de"], de) {
+		t.Errorf("CompliancePresets[de] = %+v, changed by the overrides", report.CompliancePresets["de"])
+	}
+}
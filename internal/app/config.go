@@ -0,0 +1,64 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/harnyk/wink/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// isConfigCommand tells whether the command is `wink config` or one of its subcommands
+func isConfigCommand(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.Name() == "config" && cmd.HasParent() && !cmd.Parent().HasParent() {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *app) doConfigGet(key string) error {
+	value, err := config.Get(a.settings.Config, key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+
+	return nil
+}
+
+func (a *app) doConfigSet(key string, value string) error {
+	if err := config.Set(a.settings.Files.User, key, value); err != nil {
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("%s = %s", key, value))
+
+	return nil
+}
+
+func (a *app) doConfigList() error {
+	dimmed := color.New(color.Faint).SprintFunc()
+
+	fmt.Println(dimmed("# user file     : " + a.settings.Files.User))
+	if a.settings.Files.Defaults != "" {
+		fmt.Println(dimmed("# defaults file : " + a.settings.Files.Defaults))
+	}
+
+	if a.settings.Invalid != nil {
+		fmt.Println(color.YellowString("# %s", a.settings.Invalid))
+	}
+
+	for _, key := range config.Keys() {
+		value, err := config.Get(a.settings.Config, key)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s = %s\n", key, value)
+	}
+
+	return nil
+}
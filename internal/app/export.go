@@ -0,0 +1,46 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/harnyk/wink/internal/report"
+)
+
+const (
+	exportFormatJSONLines = "jsonl"
+	exportFormatCSV       = "csv"
+)
+
+func (a *app) doExport(timeStart, timeEnd time.Time, format string, output string, mode cacheMode) error {
+	if format != exportFormatJSONLines && format != exportFormatCSV {
+		return fmt.Errorf("unknown format %q, use %s or %s", format, exportFormatJSONLines, exportFormatCSV)
+	}
+
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return err
+	}
+
+	timeSheets, err := a.loadTimesheets(authData, timeStart, timeEnd, mode)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if format == exportFormatCSV {
+		data, err = report.RenderExportCSV(timeSheets)
+	} else {
+		data, err = report.RenderExportJSONLines(timeSheets)
+	}
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+
+	return writeReportFile(output, data)
+}
@@ -0,0 +1,246 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/harnyk/wink/internal/auditlog"
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/spf13/cobra"
+)
+
+// parseHolidayRange reads --start and --end, the range defaults to the current holiday year
+func (a *app) parseHolidayRange(cmd *cobra.Command) (time.Time, time.Time, error) {
+	if cmd.Flag("start").Value.String() == "" && cmd.Flag("end").Value.String() == "" {
+		start, end := a.settings.Config.Holiday.Year(time.Now())
+		return start, end, nil
+	}
+
+	return a.parseDateRange(cmd)
+}
+
+// holidayDates returns the dates of approved holidays in the range, cached like the timesheets.
+// Holidays are only a hint for the report, so failing to fetch them is a warning.
+func (a *app) holidayDates(authData peopleapi.Auth, timeStart, timeEnd time.Time, mode cacheMode) map[string]bool {
+	if a.isLocalBackend() {
+		return nil
+	}
+
+	cache, err := a.openCache(authData)
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	fetchStart, fetchEnd := timeStart, timeEnd
+
+	var cached map[string]bool
+	if mode != cacheModeRefresh {
+		var missing []time.Time
+		cached, missing = cache.LookupHolidays(timeStart, timeEnd, now, mode == cacheModeOffline)
+
+		if mode == cacheModeOffline || len(missing) == 0 {
+			return cached
+		}
+
+		fetchStart, fetchEnd = missing[0], missing[len(missing)-1]
+	}
+
+	resp, err := a.newClient(authData).GetHolidays(fetchStart, fetchEnd)
+	if errors.Is(err, peopleapi.ErrNotSupported) {
+		return nil
+	}
+	if err != nil {
+		fmt.Println(color.YellowString("WARNING: Could not fetch holidays: %s", err))
+		return cached
+	}
+
+	fetched := peopleapi.HolidayDates(resp.Result)
+	if err := cache.StoreHolidays(fetchStart, fetchEnd, fetched, now); err != nil {
+		fmt.Println(color.YellowString("WARNING: Could not update the cache: %s", err))
+		return fetched
+	}
+
+	holidays, _ := cache.LookupHolidays(timeStart, timeEnd, now, true)
+
+	return holidays
+}
+
+func (a *app) fetchHolidays(timeStart, timeEnd time.Time) ([]peopleapi.Holiday, error) {
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.newClient(authData).GetHolidays(timeStart, timeEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Result, nil
+}
+
+func (a *app) doHolidayBalance() error {
+	yearStart, yearEnd := a.settings.Config.Holiday.Year(time.Now())
+
+	holidays, err := a.fetchHolidays(yearStart, yearEnd)
+	if err != nil {
+		return err
+	}
+
+	var taken, pending float64
+	for _, holiday := range holidays {
+		switch {
+		case holiday.IsApproved():
+			taken += holiday.Days()
+		case holiday.IsPending():
+			pending += holiday.Days()
+		}
+	}
+
+	allowance := a.settings.Config.Holiday.AllowanceDays
+
+	fmt.Printf("Holiday year : %s - %s\n", yearStart.Format("2006-01-02"), yearEnd.Format("2006-01-02"))
+	if allowance > 0 {
+		fmt.Printf("Allowance    : %s day(s)\n", formatDays(allowance))
+	}
+	fmt.Printf("Taken        : %s day(s)\n", formatDays(taken))
+	fmt.Printf("Pending      : %s day(s)\n", formatDays(pending))
+	if allowance > 0 {
+		fmt.Printf("Remaining    : %s day(s)\n", formatDays(allowance-taken-pending))
+	} else {
+		fmt.Println(color.New(color.Faint).Sprint("Set holiday.allowance_days to see the remaining days"))
+	}
+
+	return nil
+}
+
+func formatDays(days float64) string {
+	return strconv.FormatFloat(days, 'f', -1, 64)
+}
+
+func (a *app) doHolidayList(timeStart, timeEnd time.Time, asJSON bool) error {
+	holidays, err := a.fetchHolidays(timeStart, timeEnd)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		jsonData, err := json.MarshalIndent(holidays, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if len(holidays) == 0 {
+		return fmt.Errorf("no holidays found")
+	}
+
+	for _, holiday := range holidays {
+		duration := holiday.Duration + " day(s)"
+		if holiday.PartOfTheDay != "" {
+			duration = "half day " + holiday.PartOfTheDay
+		}
+
+		status := holiday.Status
+		switch {
+		case holiday.IsApproved():
+			status = color.GreenString(status)
+		case holiday.IsPending():
+			status = color.YellowString(status)
+		}
+
+		fmt.Printf("%s - %s  %-12s  %s\n", holiday.StartDate, holiday.EndDate, duration, status)
+	}
+
+	return nil
+}
+
+func (a *app) doHolidayRequest(from, to time.Time, partOfTheDay string, comments string) error {
+	workdays, err := config.ParseWeekdays(a.settings.Config.Remind.Workdays)
+	if err != nil {
+		return err
+	}
+
+	request := peopleapi.HolidayRequest{
+		StartDate:    from,
+		EndDate:      to,
+		PartOfTheDay: partOfTheDay,
+		Comments:     comments,
+	}
+
+	if partOfTheDay != "" {
+		request.Days = 0.5
+	} else {
+		request.Days = float64(peopleapi.WorkingDays(from, to, workdays))
+		if request.Days == 0 {
+			return fmt.Errorf("there are no working days from %s to %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		}
+	}
+
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.newClient(authData).RequestHoliday(request)
+	a.recordWrite(authData, auditlog.Entry{
+		Action: peopleapi.ActionAddNewHoliday,
+		Date:   from.Format("2006-01-02"),
+		Slot:   "holiday",
+		Time:   formatDays(request.Days) + "d",
+	}, resp, err)
+	if err != nil {
+		return err
+	}
+
+	if a.dryRun {
+		return nil
+	}
+
+	printSuccess(fmt.Sprintf("Requested %s day(s) of holiday from %s to %s", formatDays(request.Days), from.Format("2006-01-02"), to.Format("2006-01-02")))
+
+	return nil
+}
+
+func (a *app) doAbsenceList(timeStart, timeEnd time.Time, asJSON bool) error {
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.newClient(authData).GetAbsences(timeStart, timeEnd)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		jsonData, err := json.MarshalIndent(resp.Result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if len(resp.Result) == 0 {
+		return fmt.Errorf("no absences found")
+	}
+
+	for _, absence := range resp.Result {
+		fmt.Printf("%s - %s  %s day(s)  %s", absence.StartDate, absence.EndDate, absence.Duration, absence.Reason)
+		if absence.Status != "" {
+			fmt.Printf("  (%s)", absence.Status)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
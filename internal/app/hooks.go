@@ -0,0 +1,67 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"github.com/harnyk/wink/internal/hooks"
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+)
+
+func (a *app) newHookContext(timeSheet *peopleapi.TimeSheet, action peopleapi.ActionType, checkInTime time.Time, slot string) hooks.Context {
+	return hooks.Context{
+		Action:     strings.ToLower(string(action)),
+		Date:       time.Now(),
+		Time:       checkInTime.Format("15:04"),
+		Slot:       slot,
+		Profile:    string(a.configFileName),
+		TodayTotal: totalUpTo(timeSheet, checkInTime),
+	}
+}
+
+func (a *app) runHooks(phase string, ctx hooks.Context) error {
+	if !a.hooksEnabled() {
+		return nil
+	}
+
+	ctx.Phase = phase
+
+	return hooks.Run(a.hookCommands(phase, ctx.Action), ctx)
+}
+
+// hooksEnabled is false for a wink run by a hook, and on a dry run as hooks may have effects of their own
+func (a *app) hooksEnabled() bool {
+	return !hooks.IsNested() && !a.dryRun
+}
+
+// hookCommands returns the hooks configured for the phase of the action, "in" or "out"
+func (a *app) hookCommands(phase string, action string) []string {
+	cfg := a.settings.Config.Hooks
+
+	switch {
+	case phase == hooks.PhasePre && action == "in":
+		return cfg.PreIn
+	case phase == hooks.PhasePost && action == "in":
+		return cfg.PostIn
+	case phase == hooks.PhasePre && action == "out":
+		return cfg.PreOut
+	case phase == hooks.PhasePost && action == "out":
+		return cfg.PostOut
+	}
+	return nil
+}
+
+// totalUpTo returns the time worked today up to the given clock time
+func totalUpTo(timeSheet *peopleapi.TimeSheet, t time.Time) time.Duration {
+	if timeSheet.TimesheetDate == "" {
+		return 0
+	}
+
+	total, err := report.CalculateHours(timeSheet)
+	if err != nil {
+		return 0
+	}
+
+	return total.WorkedUpTo(t)
+}
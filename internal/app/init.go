@@ -0,0 +1,80 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/ui"
+)
+
+// askCredentials asks for the API key and the employee ID until PeopleHR accepts them
+// and the user confirms the employee, or the user decides to save them anyway.
+// The employee is nil when the credentials or the employee couldn't be checked.
+func (a *app) askCredentials(u ui.UI) (peopleapi.Auth, *peopleapi.Employee, error) {
+	for {
+		apiKey, err := u.AskString("Please enter your API key:")
+		if err != nil {
+			return peopleapi.Auth{}, nil, err
+		}
+
+		employeeID, err := u.AskString("Please enter your employee ID:")
+		if err != nil {
+			return peopleapi.Auth{}, nil, err
+		}
+
+		authData := peopleapi.Auth{APIKey: apiKey, EmployeeID: employeeID}
+
+		employee, err := peopleapi.CheckCredentials(peopleapi.NewClient(authData, a.peopleHROptions()...))
+		if err == nil && employee == nil {
+			printSuccess("PeopleHR accepts the credentials")
+			fmt.Println("The API key may not read employee details, so the employee can't be shown")
+			return authData, nil, nil
+		}
+		if err == nil {
+			printEmployee(employeeID, employee)
+
+			confirmed, err := u.Confirm("Is this you?")
+			if err != nil {
+				return authData, nil, err
+			}
+			if confirmed {
+				return authData, employee, nil
+			}
+
+			fmt.Println("Please check your employee ID")
+			continue
+		}
+
+		fmt.Println(color.RedString(credentialsProblem(err)))
+
+		choice, err := u.AskString("Re-enter the credentials (r), save them anyway (s) or quit (q)? [r]")
+		if err != nil {
+			return authData, nil, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(choice)) {
+		case "s":
+			return authData, nil, nil
+		case "q":
+			return authData, nil, fmt.Errorf("wink init cancelled")
+		}
+	}
+}
+
+// credentialsProblem explains why the credentials couldn't be checked
+func credentialsProblem(err error) string {
+	switch {
+	case errors.Is(err, peopleapi.ErrInvalidAPIKey):
+		return "PeopleHR doesn't accept the API key: " + err.Error()
+	case errors.Is(err, peopleapi.ErrNoPermission):
+		return "The API key may not read timesheets, which wink needs: " + err.Error()
+	case errors.Is(err, peopleapi.ErrUnknownEmployee):
+		return "PeopleHR doesn't know the employee ID: " + err.Error()
+	case errors.Is(err, peopleapi.ErrNetwork):
+		return "Could not reach PeopleHR to check the credentials, are you online? " + err.Error()
+	}
+	return "Could not check the credentials: " + err.Error()
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/harnyk/wink/internal/hooks"
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+const (
+	// lockTimeout is how long a check-in or check-out waits for another wink process
+	lockTimeout = 10 * time.Second
+	// writeAttempts is how often a check-in or check-out is written before giving up on a lost update
+	writeAttempts = 3
+)
+
+// errLostUpdate means the slot written holds another value when read back
+var errLostUpdate = errors.New("the check-in or check-out was overwritten")
+
+func (a *app) lockFileName() string {
+	return filepath.Join(filepath.Dir(string(a.configFileName)), "timesheet.lock")
+}
+
+// lockTimesheet serializes the read-modify-write of today's timesheet between wink processes.
+// A wink run by a hook doesn't lock, the wink running the hook holds the lock already,
+// and reads the timesheet again once the pre hooks are done.
+func (a *app) lockTimesheet() (unlock func(), err error) {
+	if hooks.IsNested() {
+		return func() {}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(a.lockFileName()), 0700); err != nil {
+		return nil, err
+	}
+
+	lock := flock.New(a.lockFileName())
+
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	locked, err := lock.TryLockContext(ctx, 100*time.Millisecond)
+	if err != nil || !locked {
+		return nil, fmt.Errorf("another wink is changing the timesheet, gave up waiting after %s", lockTimeout)
+	}
+
+	return func() { lock.Unlock() }, nil
+}
+
+// verifyWrite reads today's timesheet back and returns what the slot holds
+func verifyWrite(client peopleapi.Client, slot string) (string, error) {
+	timeSheet, err := readToday(client)
+	if err != nil {
+		return "", err
+	}
+
+	return timeSheet.Slot(slot), nil
+}
+
+// verifySlots reads the timesheet of the date back and checks the slots hold the times written,
+// a slot holding another time is a lost update
+func verifySlots(client peopleapi.Client, date time.Time, written map[string]string) error {
+	result, err := client.GetTimesheet(date, date)
+	if err != nil {
+		return err
+	}
+
+	timeSheet := peopleapi.TimeSheet{}
+	if len(result.Result) > 0 {
+		timeSheet = result.Result[0]
+	}
+
+	for _, slot := range peopleapi.SlotNames {
+		timeStr, ok := written[slot]
+		if !ok {
+			continue
+		}
+		if value := timeSheet.Slot(slot); !peopleapi.SameClock(value, timeStr) {
+			return fmt.Errorf("%w: %s of %s holds %s instead of %s", errLostUpdate, slot, date.Format("2006-01-02"), displaySlot(value), timeStr)
+		}
+	}
+
+	return nil
+}
+
+// readToday fetches today's timesheet, empty if there is none
+func readToday(client peopleapi.Client) (peopleapi.TimeSheet, error) {
+	result, err := client.GetTimesheet(time.Time{}, time.Time{})
+	if err != nil {
+		return peopleapi.TimeSheet{}, err
+	}
+	if len(result.Result) == 0 {
+		return peopleapi.TimeSheet{}, nil
+	}
+
+	return result.Result[0], nil
+}
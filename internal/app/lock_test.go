@@ -0,0 +1,180 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+// racingClient lets another check-in and check-out land on the slot of each of the first races writes,
+// the way a change made in the web UI at the same moment would
+type racingClient struct {
+	peopleapi.Client
+	races *int
+}
+
+func (c racingClient) CheckInOut(slot string, timeStr string) (*peopleapi.EditResponse, error) {
+	resp, err := c.Client.CheckInOut(slot, timeStr)
+	if err != nil || *c.races == 0 {
+		return resp, err
+	}
+	*c.races--
+
+	hour := 12 - *c.races
+	if _, err := c.Client.CheckInOut(slot, fmt.Sprintf("%02d:00", hour)); err != nil {
+		return nil, err
+	}
+	return c.Client.CheckInOut(peopleapi.SlotNames[peopleapi.SlotIndex(slot)+1], fmt.Sprintf("%02d:30", hour))
+}
+
+// overwritingUpdates stores another time than the one sent in the slot
+type overwritingUpdates struct {
+	peopleapi.Client
+	slot string
+}
+
+func (c overwritingUpdates) UpdateTimesheet(timeSheet peopleapi.TimeSheet) (*peopleapi.EditResponse, error) {
+	timeSheet.SetSlot(c.slot, "23:00:00")
+	return c.Client.UpdateTimesheet(timeSheet)
+}
+
+func TestLockTimesheet(t *testing.T) {
+	a := newTestApp(t)
+
+	unlock, err := a.lockTimesheet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const held = 300 * time.Millisecond
+	go func() {
+		time.Sleep(held)
+		unlock()
+	}()
+
+	start := time.Now()
+	unlockAgain, err := a.lockTimesheet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlockAgain()
+
+	if waited := time.Since(start); waited < held {
+		t.Errorf("lockTimesheet() returned after %s while the lock was held for %s", waited, held)
+	}
+}
+
+func TestWriteCheckInOutLostUpdate(t *testing.T) {
+	today := time.Now()
+	date := today.Format("2006-01-02")
+
+	tests := []struct {
+		name      string
+		races     int
+		wantErr   error
+		wantToday peopleapi.TimeSheet
+	}{
+		{
+			name:  "retried with the next free slot",
+			races: 1,
+			wantToday: peopleapi.TimeSheet{TimesheetDate: date, TimeIn1: "08:00:00", TimeOut1: "09:00:00",
+				TimeIn2: "12:00:00", TimeOut2: "12:30:00", TimeIn3: "17:00:00"},
+		},
+		{
+			name:    "overwritten on every attempt",
+			races:   writeAttempts,
+			wantErr: errLostUpdate,
+			wantToday: peopleapi.TimeSheet{TimesheetDate: date, TimeIn1: "08:00:00", TimeOut1: "09:00:00",
+				TimeIn2: "10:00:00", TimeOut2: "10:30:00", TimeIn3: "11:00:00", TimeOut3: "11:30:00", TimeIn4: "12:00:00", TimeOut4: "12:30:00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t)
+			writeTimesheets(t, a, peopleapi.TimeSheet{TimesheetDate: date, TimeIn1: "08:00:00", TimeOut1: "09:00:00"})
+
+			races := tt.races
+			client := racingClient{Client: a.newClient(peopleapi.Auth{}), races: &races}
+			checkInTime := time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC)
+
+			err := a.writeCheckInOut(client, peopleapi.Auth{}, peopleapi.ActionTypeIn, checkInTime)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("writeCheckInOut() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := readTimesheet(t, a, today); got != tt.wantToday {
+				t.Errorf("today = %+v, want %+v", got, tt.wantToday)
+			}
+		})
+	}
+}
+
+func TestCheckOutOvernightLostUpdate(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	a := newTestApp(t)
+	writeTimesheets(t, a, peopleapi.TimeSheet{TimesheetDate: yesterday.Format("2006-01-02"), TimeIn1: "22:00:00"})
+
+	client := overwritingUpdates{Client: a.newClient(peopleapi.Auth{}), slot: "TimeOut1"}
+	checkOutTime := time.Date(0, 1, 1, 2, 0, 0, 0, time.UTC)
+
+	err := a.writeCheckInOut(client, peopleapi.Auth{}, peopleapi.ActionTypeOut, checkOutTime)
+	if !errors.Is(err, errLostUpdate) {
+		t.Fatalf("writeCheckInOut() error = %v, want %v", err, errLostUpdate)
+	}
+}
+
+func TestCheckInOutAfterPreHook(t *testing.T) {
+	today := time.Now()
+	date := today.Format("2006-01-02")
+
+	tests := []struct {
+		name      string
+		action    peopleapi.ActionType
+		before    peopleapi.TimeSheet
+		byHook    peopleapi.TimeSheet
+		wantErr   error
+		wantToday peopleapi.TimeSheet
+	}{
+		{
+			name:      "check-in after the hook checked in and out",
+			action:    peopleapi.ActionTypeIn,
+			before:    peopleapi.TimeSheet{TimesheetDate: date, TimeIn1: "08:00:00", TimeOut1: "10:00:00"},
+			byHook:    peopleapi.TimeSheet{TimesheetDate: date, TimeIn1: "08:00:00", TimeOut1: "10:00:00", TimeIn2: "11:00:00", TimeOut2: "12:00:00"},
+			wantToday: peopleapi.TimeSheet{TimesheetDate: date, TimeIn1: "08:00:00", TimeOut1: "10:00:00", TimeIn2: "11:00:00", TimeOut2: "12:00:00", TimeIn3: "13:00:00"},
+		},
+		{
+			name:      "check-out after the hook checked out",
+			action:    peopleapi.ActionTypeOut,
+			before:    peopleapi.TimeSheet{TimesheetDate: date, TimeIn1: "08:00:00"},
+			byHook:    peopleapi.TimeSheet{TimesheetDate: date, TimeIn1: "08:00:00", TimeOut1: "12:00:00"},
+			wantErr:   errCantCheckOut,
+			wantToday: peopleapi.TimeSheet{TimesheetDate: date, TimeIn1: "08:00:00", TimeOut1: "12:00:00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t)
+			writeTimesheets(t, a, tt.before)
+
+			// the hook stands for a nested wink, it swaps in the timesheet it would leave
+			hook := newTestApp(t)
+			writeTimesheets(t, hook, tt.byHook)
+			command := fmt.Sprintf("cp '%s' '%s'", hook.localFileName(), a.localFileName())
+			a.settings.Config.Hooks.PreIn = []string{command}
+			a.settings.Config.Hooks.PreOut = []string{command}
+
+			err := a.checkInOut(peopleapi.Auth{}, tt.action, time.Date(0, 1, 1, 13, 0, 0, 0, time.UTC))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkInOut() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := readTimesheet(t, a, today); got != tt.wantToday {
+				t.Errorf("today = %+v, want %+v", got, tt.wantToday)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/harnyk/wink/internal/auditlog"
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+func (a *app) auditLogFileName() string {
+	return filepath.Join(filepath.Dir(string(a.configFileName)), "audit.log")
+}
+
+// recordWrite appends a write sent to PeopleHR to the audit log, the entry
+// carries the action, slot and time, the date defaults to today.
+// Failing to record is reported, but doesn't fail the action itself.
+func (a *app) recordWrite(authData peopleapi.Auth, entry auditlog.Entry, resp *peopleapi.EditResponse, writeErr error) {
+	// nothing was sent on a dry run
+	if a.dryRun {
+		return
+	}
+
+	wallClock := time.Now()
+
+	entry.Timestamp = wallClock
+	entry.WallClock = wallClock
+	entry.Profile = string(a.configFileName)
+	entry.EmployeeID = authData.EmployeeID
+	if entry.Date == "" {
+		entry.Date = wallClock.Format("2006-01-02")
+	}
+
+	if a.clockOffset != nil {
+		offset := a.clockOffset.Seconds()
+		entry.NTPOffset = &offset
+		entry.Timestamp = wallClock.Add(-*a.clockOffset)
+	}
+
+	if resp != nil {
+		entry.Response.Status = resp.Status
+		entry.Response.Message = resp.Message
+		entry.Response.IsError = resp.IsError
+	}
+	if writeErr != nil {
+		entry.Response.Error = writeErr.Error()
+	}
+
+	if err := auditlog.NewLog(a.auditLogFileName()).Append(entry); err != nil {
+		fmt.Println(color.YellowString("WARNING: Could not write the audit log: %s", err))
+	}
+}
+
+func (a *app) doLog(filter auditlog.Filter, asJSON bool) error {
+	entries, err := auditlog.NewLog(a.auditLogFileName()).Read(filter)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		jsonData, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		return fmt.Errorf("no log entries found")
+	}
+
+	dimmed := color.New(color.Faint).SprintFunc()
+
+	for _, entry := range entries {
+		status := color.GreenString("ok")
+		if entry.Response.Error != "" {
+			status = color.RedString(entry.Response.Error)
+		} else if entry.Response.Message != "" {
+			status = color.GreenString(entry.Response.Message)
+		}
+
+		offset := "ntp ?"
+		if entry.NTPOffset != nil {
+			offset = fmt.Sprintf("ntp %+.1fs", *entry.NTPOffset)
+		}
+
+		fmt.Printf("%s %s %-18s %-9s %s %s %s\n",
+			entry.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			dimmed(entry.Date),
+			entry.Action,
+			entry.Slot,
+			entry.Time,
+			dimmed(offset),
+			status,
+		)
+	}
+
+	return nil
+}
+
+func (a *app) doReconcile(timeStart, timeEnd time.Time, asJSON bool) error {
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return err
+	}
+
+	entries, err := auditlog.NewLog(a.auditLogFileName()).Read(auditlog.Filter{
+		Since:      timeStart,
+		Until:      timeEnd,
+		EmployeeID: authData.EmployeeID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// always compare with what is on the server right now
+	timeSheets, err := a.loadTimesheets(authData, timeStart, timeEnd, cacheModeRefresh)
+	if err != nil {
+		return err
+	}
+
+	discrepancies := auditlog.Reconcile(entries, timeSheets)
+
+	if asJSON {
+		jsonData, err := json.MarshalIndent(discrepancies, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	fmt.Println()
+
+	if len(discrepancies) == 0 {
+		printSuccess("The server timesheets match the audit log")
+		return nil
+	}
+
+	for _, d := range discrepancies {
+		switch d.Kind {
+		case auditlog.DiscrepancyChanged:
+			fmt.Printf("%s %-9s %s %s -> %s\n", d.Date, d.Slot, color.YellowString("changed"), d.Local, d.Server)
+		case auditlog.DiscrepancyRemoved:
+			fmt.Printf("%s %-9s %s %s\n", d.Date, d.Slot, color.RedString("removed"), d.Local)
+		case auditlog.DiscrepancyAdded:
+			fmt.Printf("%s %-9s %s %s\n", d.Date, d.Slot, color.CyanString("added  "), d.Server)
+		}
+	}
+
+	return nil
+}
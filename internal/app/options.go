@@ -1,4 +1,14 @@
 package app
 
+import "github.com/harnyk/wink/internal/config"
+
 type Version string
 type ConfigFileName string
+
+// Settings is the layered non-secret configuration, and the files it was loaded from
+type Settings struct {
+	Config config.Config
+	Files  config.Files
+	// Invalid is why the configuration failed validation, only the config commands run then
+	Invalid error
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/harnyk/wink/internal/auditlog"
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/hooks"
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+var errCantCheckOut = errors.New("you can't check out")
+
+// checkOutOvernight closes the interval left open on yesterday's timesheet,
+// for a check-out after midnight, as the overnight setting says.
+// A check-out after overnight_until, or one making the shift longer than overnight_max_shift,
+// is refused: the interval was most likely left open by mistake.
+// current is today's timesheet, it has no intervals.
+func (a *app) checkOutOvernight(client peopleapi.Client, authData peopleapi.Auth, current peopleapi.TimeSheet, checkOutTime time.Time) error {
+	cfg := a.settings.Config
+
+	policy := cfg.Overnight
+	if policy == config.OvernightOff {
+		return errCantCheckOut
+	}
+
+	until, err := config.ClockTime(cfg.OvernightUntil)
+	if err != nil {
+		return err
+	}
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	result, err := client.GetTimesheet(yesterday, yesterday)
+	if err != nil {
+		return err
+	}
+	if len(result.Result) == 0 {
+		return errCantCheckOut
+	}
+
+	previous := result.Result[0]
+
+	previousDay, err := peopleapi.ParseDay(previous)
+	if err != nil || !previousDay.IsOpen() {
+		return errCantCheckOut
+	}
+
+	open := previousDay.Intervals[len(previousDay.Intervals)-1]
+	timeStr := checkOutTime.Format("15:04")
+	startedAt := peopleapi.FormatClock(open.In)[:5]
+
+	clock := time.Duration(checkOutTime.Hour())*time.Hour + time.Duration(checkOutTime.Minute())*time.Minute
+	if clock > until {
+		return fmt.Errorf("%w, yesterday's interval since %s is still open and %s is after overnight_until %s. "+
+			"Close it in %s", errCantCheckOut, startedAt, timeStr, cfg.OvernightUntil, a.timesheetEditor())
+	}
+	if shift := 24*time.Hour + clock - open.In; shift > time.Duration(cfg.OvernightMaxShift) {
+		return fmt.Errorf("%w, closing yesterday's interval since %s at %s makes a %s shift, longer than overnight_max_shift %s. "+
+			"Close it in %s", errCantCheckOut, startedAt, timeStr, shift, time.Duration(cfg.OvernightMaxShift), a.timesheetEditor())
+	}
+
+	closeAt := timeStr
+	if policy == config.OvernightSplit {
+		closeAt = "00:00"
+	}
+	if err := previous.SetSlot(open.OutSlot(), closeAt+":00"); err != nil {
+		return err
+	}
+
+	fmt.Printf("Checking out of the shift started on %s at %s\n", previous.TimesheetDate, startedAt)
+
+	// the hooks see the timesheet the check-out ends up on
+	hookContext := a.newHookContext(&previous, peopleapi.ActionTypeOut, checkOutTime, open.OutSlot())
+	hookContext.Date = previousDay.Date
+
+	today := peopleapi.TimeSheet{TimesheetDate: time.Now().Format("2006-01-02"), TimeIn1: "00:00:00", TimeOut1: timeStr + ":00"}
+	if policy == config.OvernightSplit {
+		hookContext = a.newHookContext(&today, peopleapi.ActionTypeOut, checkOutTime, "TimeOut1")
+	}
+
+	if err := a.runHooks(hooks.PhasePre, hookContext); err != nil {
+		return err
+	}
+
+	resp, err := client.UpdateTimesheet(previous)
+	a.recordWrite(authData, auditlog.Entry{Action: peopleapi.ActionUpdateTimesheet, Date: previous.TimesheetDate, Slot: open.OutSlot(), Time: closeAt}, resp, err)
+	if err != nil {
+		return err
+	}
+	if !a.dryRun {
+		a.invalidateDay(authData, yesterday)
+
+		if err := verifySlots(client, yesterday, map[string]string{open.OutSlot(): closeAt}); err != nil {
+			return fmt.Errorf("%w. Fix yesterday's timesheet in %s", err, a.timesheetEditor())
+		}
+	}
+
+	if policy == config.OvernightSplit {
+		// yesterday is closed already, a failure from here on leaves today's part for the user to add
+		var resp *peopleapi.EditResponse
+		if current.TimesheetDate == "" {
+			resp, err = client.CreateNewTimesheet("00:00")
+			a.recordWrite(authData, auditlog.Entry{Action: peopleapi.ActionCreateNewTimesheet, Slot: "TimeIn1", Time: "00:00"}, resp, err)
+		} else {
+			// today's timesheet is there but empty, creating it again fails
+			resp, err = client.CheckInOut("TimeIn1", "00:00")
+			a.recordWrite(authData, auditlog.Entry{Action: peopleapi.ActionUpdateTimesheet, Slot: "TimeIn1", Time: "00:00"}, resp, err)
+		}
+		if err != nil {
+			return fmt.Errorf("yesterday's shift was closed at 00:00, but today's part from 00:00 to %s wasn't written: %w. "+
+				"Add it in %s", timeStr, err, a.timesheetEditor())
+		}
+
+		resp, err = client.CheckInOut("TimeOut1", timeStr)
+		a.recordWrite(authData, auditlog.Entry{Action: peopleapi.ActionUpdateTimesheet, Slot: "TimeOut1", Time: timeStr}, resp, err)
+		if err != nil {
+			return fmt.Errorf("yesterday's shift was closed at 00:00 and today's check-in at 00:00 was written, but not the check-out at %s: %w. "+
+				"Add it with `wink out %s`", timeStr, err, timeStr)
+		}
+
+		if !a.dryRun {
+			if err := verifySlots(client, time.Now(), map[string]string{"TimeIn1": "00:00", "TimeOut1": timeStr}); err != nil {
+				return fmt.Errorf("%w. Fix today's timesheet in %s", err, a.timesheetEditor())
+			}
+		}
+	}
+
+	if err := a.runHooks(hooks.PhasePost, hookContext); err != nil {
+		fmt.Println(color.YellowString("WARNING: %s", err))
+	}
+
+	return nil
+}
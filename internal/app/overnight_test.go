@@ -0,0 +1,183 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+func TestCheckOutOvernight(t *testing.T) {
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+
+	tests := []struct {
+		name          string
+		policy        string
+		noOvernight   bool
+		checkIn       string
+		today         *peopleapi.TimeSheet
+		checkOut      string
+		wantErr       bool
+		wantYesterday peopleapi.TimeSheet
+		wantToday     peopleapi.TimeSheet
+	}{
+		{
+			name:          "previous day",
+			policy:        config.OvernightPreviousDay,
+			checkIn:       "22:00:00",
+			checkOut:      "02:00",
+			wantYesterday: peopleapi.TimeSheet{TimesheetDate: yesterday, TimeIn1: "22:00:00", TimeOut1: "02:00:00"},
+		},
+		{
+			name:          "split",
+			policy:        config.OvernightSplit,
+			checkIn:       "22:00:00",
+			checkOut:      "02:00",
+			wantYesterday: peopleapi.TimeSheet{TimesheetDate: yesterday, TimeIn1: "22:00:00", TimeOut1: "00:00:00"},
+			wantToday:     peopleapi.TimeSheet{TimesheetDate: today, TimeIn1: "00:00:00", TimeOut1: "02:00:00"},
+		},
+		{
+			name:          "split onto today's empty timesheet",
+			policy:        config.OvernightSplit,
+			checkIn:       "22:00:00",
+			today:         &peopleapi.TimeSheet{TimesheetDate: today},
+			checkOut:      "02:00",
+			wantYesterday: peopleapi.TimeSheet{TimesheetDate: yesterday, TimeIn1: "22:00:00", TimeOut1: "00:00:00"},
+			wantToday:     peopleapi.TimeSheet{TimesheetDate: today, TimeIn1: "00:00:00", TimeOut1: "02:00:00"},
+		},
+		{
+			name:          "after overnight_until",
+			policy:        config.OvernightPreviousDay,
+			checkIn:       "09:00:00",
+			checkOut:      "17:00",
+			wantErr:       true,
+			wantYesterday: peopleapi.TimeSheet{TimesheetDate: yesterday, TimeIn1: "09:00:00"},
+		},
+		{
+			name:          "longer than overnight_max_shift",
+			policy:        config.OvernightPreviousDay,
+			checkIn:       "09:00:00",
+			checkOut:      "05:00",
+			wantErr:       true,
+			wantYesterday: peopleapi.TimeSheet{TimesheetDate: yesterday, TimeIn1: "09:00:00"},
+		},
+		{
+			name:          "off",
+			policy:        config.OvernightOff,
+			checkIn:       "22:00:00",
+			checkOut:      "02:00",
+			wantErr:       true,
+			wantYesterday: peopleapi.TimeSheet{TimesheetDate: yesterday, TimeIn1: "22:00:00"},
+		},
+		{
+			name:          "no overnight",
+			policy:        config.OvernightPreviousDay,
+			noOvernight:   true,
+			checkIn:       "22:00:00",
+			checkOut:      "02:00",
+			wantErr:       true,
+			wantYesterday: peopleapi.TimeSheet{TimesheetDate: yesterday, TimeIn1: "22:00:00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t)
+			a.settings.Config.Overnight = tt.policy
+			a.noOvernight = tt.noOvernight
+			timeSheets := []peopleapi.TimeSheet{{TimesheetDate: yesterday, TimeIn1: tt.checkIn}}
+			if tt.today != nil {
+				timeSheets = append(timeSheets, *tt.today)
+			}
+			writeTimesheets(t, a, timeSheets...)
+
+			checkOutTime, err := time.Parse("15:04", tt.checkOut)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = a.checkInOut(peopleapi.Auth{}, peopleapi.ActionTypeOut, checkOutTime)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkInOut() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, errCantCheckOut) {
+				t.Errorf("checkInOut() error = %v, want %v", err, errCantCheckOut)
+			}
+
+			if got := readTimesheet(t, a, now.AddDate(0, 0, -1)); got != tt.wantYesterday {
+				t.Errorf("yesterday = %+v, want %+v", got, tt.wantYesterday)
+			}
+			if got := readTimesheet(t, a, now); got != tt.wantToday {
+				t.Errorf("today = %+v, want %+v", got, tt.wantToday)
+			}
+		})
+	}
+}
+
+// failingCheckInOut fails every check-in and check-out after the first ones
+type failingCheckInOut struct {
+	peopleapi.Client
+	succeed *int
+}
+
+func (c failingCheckInOut) CheckInOut(slot string, timeStr string) (*peopleapi.EditResponse, error) {
+	if *c.succeed == 0 {
+		return nil, errors.New("server response: try again later")
+	}
+	*c.succeed--
+	return c.Client.CheckInOut(slot, timeStr)
+}
+
+func TestCheckOutOvernightSplitFailure(t *testing.T) {
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+
+	tests := []struct {
+		name      string
+		succeed   int
+		wantErr   string
+		wantToday peopleapi.TimeSheet
+	}{
+		{
+			name:      "check-in fails",
+			wantErr:   "today's part from 00:00 to 02:00 wasn't written",
+			wantToday: peopleapi.TimeSheet{TimesheetDate: today},
+		},
+		{
+			name:      "check-out fails",
+			succeed:   1,
+			wantErr:   "Add it with `wink out 02:00`",
+			wantToday: peopleapi.TimeSheet{TimesheetDate: today, TimeIn1: "00:00:00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestApp(t)
+			a.settings.Config.Overnight = config.OvernightSplit
+			writeTimesheets(t, a,
+				peopleapi.TimeSheet{TimesheetDate: yesterday, TimeIn1: "22:00:00"},
+				peopleapi.TimeSheet{TimesheetDate: today})
+
+			succeed := tt.succeed
+			client := failingCheckInOut{Client: a.newClient(peopleapi.Auth{}), succeed: &succeed}
+
+			err := a.writeCheckInOut(client, peopleapi.Auth{}, peopleapi.ActionTypeOut, time.Date(0, 1, 1, 2, 0, 0, 0, time.UTC))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("writeCheckInOut() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+
+			wantYesterday := peopleapi.TimeSheet{TimesheetDate: yesterday, TimeIn1: "22:00:00", TimeOut1: "00:00:00"}
+			if got := readTimesheet(t, a, now.AddDate(0, 0, -1)); got != wantYesterday {
+				t.Errorf("yesterday = %+v, want %+v", got, wantYesterday)
+			}
+			if got := readTimesheet(t, a, now); got != tt.wantToday {
+				t.Errorf("today = %+v, want %+v", got, tt.wantToday)
+			}
+		})
+	}
+}
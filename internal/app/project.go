@@ -0,0 +1,134 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/harnyk/wink/internal/auditlog"
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+)
+
+// parseProjectDuration accepts a Go duration like 1h30m, HH:MM or decimal hours
+func parseProjectDuration(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, nil
+	}
+
+	d, err := peopleapi.ParseHours(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, use e.g. 1h30m, 1:30 or 1.5", s)
+	}
+	if d == 0 {
+		return 0, fmt.Errorf("duration must not be zero")
+	}
+
+	return d, nil
+}
+
+func (a *app) doProjectLog(project string, task string, durationArg string, date time.Time, notes string) error {
+	duration, err := parseProjectDuration(durationArg)
+	if err != nil {
+		return err
+	}
+
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return err
+	}
+
+	entry := peopleapi.ProjectTimesheet{
+		ProjectTimesheetDate: date.Format("2006-01-02"),
+		Project:              project,
+		Task:                 task,
+		TotalHours:           peopleapi.FormatHours(duration),
+		Notes:                notes,
+	}
+
+	client := a.newClient(authData)
+
+	resp, err := client.AddProjectTimesheet(entry)
+	a.recordWrite(authData, auditlog.Entry{
+		Action: peopleapi.ActionAddProjectTimesheet,
+		Date:   entry.ProjectTimesheetDate,
+		Slot:   project + "/" + task,
+		Time:   entry.TotalHours,
+	}, resp, err)
+	if err != nil {
+		return err
+	}
+
+	if a.dryRun {
+		return nil
+	}
+
+	printSuccess(fmt.Sprintf("Logged %s on %s / %s for %s", report.FormatDuration(duration), project, task, entry.ProjectTimesheetDate))
+
+	return nil
+}
+
+func (a *app) fetchProjectTimesheets(timeStart, timeEnd time.Time) ([]peopleapi.ProjectTimesheet, error) {
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.newClient(authData).GetProjectTimesheet(timeStart, timeEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Result, nil
+}
+
+func (a *app) doProjectList(timeStart, timeEnd time.Time, asJSON bool) error {
+	entries, err := a.fetchProjectTimesheets(timeStart, timeEnd)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		jsonData, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		return fmt.Errorf("no project entries found")
+	}
+
+	for _, entry := range entries {
+		fmt.Printf("%s  %-6s  %s / %s", entry.ProjectTimesheetDate, entry.TotalHours, entry.Project, entry.Task)
+		if entry.Notes != "" {
+			fmt.Printf("  - %s", entry.Notes)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func (a *app) doProjectReport(timeStart, timeEnd time.Time, opts reportOptions) error {
+	entries, err := a.fetchProjectTimesheets(timeStart, timeEnd)
+	if err != nil {
+		return err
+	}
+
+	if opts.output != "" {
+		jsonStr, err := report.RenderProjectReportJSON(entries)
+		if err != nil {
+			return err
+		}
+
+		return writeReportFile(opts.output, jsonStr)
+	}
+
+	fmt.Println()
+	fmt.Println(report.RenderProjectReport(timeStart, timeEnd, entries, a.renderOptions(opts)))
+
+	return nil
+}
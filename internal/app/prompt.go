@@ -0,0 +1,170 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/harnyk/wink/internal/auth"
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/status"
+	"github.com/harnyk/wink/internal/tscache"
+)
+
+// promptRefreshInterval is how often the prompt may start a background refresh
+const promptRefreshInterval = time.Duration(1 * time.Minute)
+
+func (a *app) doPrompt(format string, output string) error {
+	if format == "" {
+		format = a.settings.Config.Prompt.Format
+	}
+	if format == "" {
+		format = status.DefaultFormat
+	}
+
+	text, err := status.Render(a.promptStatus(), format, output)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(text)
+
+	return nil
+}
+
+// promptStatus reads today's status from the prompt snapshot only. It never asks for
+// the password nor runs auth.password_command, decrypting the cache could block.
+func (a *app) promptStatus() status.Status {
+	if a.isLocalBackend() {
+		return a.localPromptStatus()
+	}
+
+	now := time.Now()
+
+	snapshot, ok := a.readPromptSnapshot()
+	if !ok || snapshot.Date != now.Format("2006-01-02") {
+		a.refreshInBackground(now)
+		return status.Status{}
+	}
+
+	stale := now.Sub(snapshot.FetchedAt) > tscache.TTLToday
+	if stale {
+		a.refreshInBackground(now)
+	}
+
+	s := snapshot.Status.Status(now)
+	s.Stale = stale
+
+	return s
+}
+
+// promptSnapshot is today's status as last fetched, kept unencrypted next to the cache
+// so that the prompt can read it without the password. It holds only what the prompt shows,
+// the timesheet itself stays in the encrypted cache.
+type promptSnapshot struct {
+	Date   string
+	Status status.Snapshot
+	// FetchedAt is zero after a write to today's timesheet
+	FetchedAt time.Time
+}
+
+func (a *app) promptFileName() string {
+	return filepath.Join(filepath.Dir(string(a.configFileName)), "prompt.json")
+}
+
+func (a *app) readPromptSnapshot() (promptSnapshot, bool) {
+	var snapshot promptSnapshot
+
+	data, err := os.ReadFile(a.promptFileName())
+	if err != nil {
+		return snapshot, false
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, false
+	}
+
+	return snapshot, true
+}
+
+func (a *app) writePromptSnapshot(snapshot promptSnapshot) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return
+	}
+	os.WriteFile(a.promptFileName(), data, 0600)
+}
+
+// savePromptSnapshot keeps today's status out of the timesheets fetched for the prompt
+func (a *app) savePromptSnapshot(timeSheets []peopleapi.TimeSheet, now time.Time) {
+	snapshot := promptSnapshot{Date: now.Format("2006-01-02"), FetchedAt: now}
+
+	var todaySheet *peopleapi.TimeSheet
+	for i := range timeSheets {
+		if timeSheets[i].TimesheetDate == snapshot.Date {
+			todaySheet = &timeSheets[i]
+		}
+	}
+	snapshot.Status = status.NewSnapshot(todaySheet)
+
+	a.writePromptSnapshot(snapshot)
+}
+
+// expirePromptSnapshot marks the snapshot out of date, today's timesheet has changed
+func (a *app) expirePromptSnapshot() {
+	snapshot, ok := a.readPromptSnapshot()
+	if !ok {
+		return
+	}
+
+	snapshot.FetchedAt = time.Time{}
+	a.writePromptSnapshot(snapshot)
+}
+
+// localPromptStatus reads today's status from the file of the local backend
+func (a *app) localPromptStatus() status.Status {
+	now := time.Now()
+
+	resp, err := a.newClient(peopleapi.Auth{}).GetTimesheet(now, now)
+	if err != nil {
+		return status.Status{}
+	}
+
+	var todaySheet *peopleapi.TimeSheet
+	if len(resp.Result) > 0 {
+		todaySheet = &resp.Result[0]
+	}
+
+	return status.FromTimeSheet(todaySheet, now)
+}
+
+// refreshInBackground starts `wink cache refresh` for today without waiting for it,
+// at most once per promptRefreshInterval. The refresh needs an unattended password source.
+func (a *app) refreshInBackground(now time.Time) {
+	if os.Getenv(auth.PasswordEnv) == "" && a.settings.Config.Auth.PasswordCommand == "" {
+		return
+	}
+
+	marker := filepath.Join(filepath.Dir(string(a.configFileName)), "prompt-refresh")
+
+	if info, err := os.Stat(marker); err == nil && now.Sub(info.ModTime()) < promptRefreshInterval {
+		return
+	}
+	if err := os.WriteFile(marker, nil, 0600); err != nil {
+		return
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return
+	}
+
+	today := now.Format(a.settings.Config.Report.DateFormat)
+	cmd := exec.Command(executable, "cache", "refresh", "--start", today, "--end", today)
+	if err := cmd.Start(); err != nil {
+		return
+	}
+	cmd.Process.Release()
+}
@@ -0,0 +1,155 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/remind"
+)
+
+func (a *app) remindRules() (remind.Rules, error) {
+	cfg := a.settings.Config.Remind
+	rules := remind.Rules{
+		MaxDaily: time.Duration(cfg.MaxDaily),
+	}
+
+	var err error
+	if cfg.CheckoutAfter != "" {
+		if rules.CheckoutAfter, err = config.ClockTime(cfg.CheckoutAfter); err != nil {
+			return rules, err
+		}
+	}
+	if cfg.CheckinBy != "" {
+		if rules.CheckinBy, err = config.ClockTime(cfg.CheckinBy); err != nil {
+			return rules, err
+		}
+	}
+	if rules.Workdays, err = config.ParseWeekdays(cfg.Workdays); err != nil {
+		return rules, err
+	}
+
+	if rules.CheckoutAfter == 0 && rules.CheckinBy == 0 && rules.MaxDaily == 0 {
+		return rules, fmt.Errorf("nothing to remind about, set remind.checkout_after, remind.checkin_by or remind.max_daily")
+	}
+
+	return rules, nil
+}
+
+// todayReminders returns the reminders due for today's timesheet
+func (a *app) todayReminders(authData peopleapi.Auth, rules remind.Rules) ([]remind.Reminder, error) {
+	today := time.Now()
+
+	timeSheets, err := a.loadTimesheets(authData, today, today, cacheModeDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	var todaySheet *peopleapi.TimeSheet
+	for i := range timeSheets {
+		if timeSheets[i].TimesheetDate == today.Format("2006-01-02") {
+			todaySheet = &timeSheets[i]
+		}
+	}
+
+	return remind.Check(todaySheet, today, rules)
+}
+
+// remindedFileName keeps the kinds of reminder sent today, each is sent once a day
+// even when `wink remind` is run over and over by a timer
+func (a *app) remindedFileName() string {
+	return filepath.Join(filepath.Dir(string(a.configFileName)), "reminded.json")
+}
+
+// sentReminders are the kinds of reminder sent on the date
+type sentReminders struct {
+	Date  string   `json:"date"`
+	Kinds []string `json:"kinds"`
+}
+
+// readSentReminders returns the kinds of reminder sent on the date, none if the file is missing or of another day
+func (a *app) readSentReminders(date string) sentReminders {
+	sent := sentReminders{Date: date}
+
+	data, err := os.ReadFile(a.remindedFileName())
+	if err != nil {
+		return sent
+	}
+
+	var stored sentReminders
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Date != date {
+		return sent
+	}
+
+	return stored
+}
+
+func (a *app) writeSentReminders(sent sentReminders) error {
+	data, err := json.Marshal(sent)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.remindedFileName(), data, 0600)
+}
+
+// sendReminders notifies about the reminders due today which weren't sent yet today
+func (a *app) sendReminders(authData peopleapi.Auth, rules remind.Rules) error {
+	reminders, err := a.todayReminders(authData, rules)
+	if err != nil {
+		return err
+	}
+
+	sent := a.readSentReminders(time.Now().Format("2006-01-02"))
+	done := map[string]bool{}
+	for _, kind := range sent.Kinds {
+		done[kind] = true
+	}
+
+	for _, reminder := range reminders {
+		if done[reminder.Kind] {
+			continue
+		}
+		if err := remind.Notify(a.settings.Config.Remind.Notify, reminder); err != nil {
+			return err
+		}
+
+		sent.Kinds = append(sent.Kinds, reminder.Kind)
+		if err := a.writeSentReminders(sent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *app) doRemind(daemon bool) error {
+	rules, err := a.remindRules()
+	if err != nil {
+		return err
+	}
+
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return err
+	}
+
+	if !daemon {
+		return a.sendReminders(authData, rules)
+	}
+
+	interval := time.Duration(a.settings.Config.Remind.Interval)
+	fmt.Printf("Checking every %s, press Ctrl-C to stop\n", interval)
+
+	for {
+		if err := a.sendReminders(authData, rules); err != nil {
+			fmt.Println(color.YellowString("WARNING: %s", err))
+		}
+
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/remind"
+)
+
+func TestSendRemindersOncePerDay(t *testing.T) {
+	a := newTestApp(t)
+	writeTimesheets(t, a, peopleapi.TimeSheet{TimesheetDate: time.Now().Format("2006-01-02"), TimeIn1: "00:00:00"})
+
+	notified := filepath.Join(t.TempDir(), "notified")
+	a.settings.Config.Remind.Notify = "echo $WINK_REMINDER >> '" + notified + "'"
+	a.settings.Config.Remind.MaxDaily = config.Duration(time.Nanosecond)
+
+	rules, err := a.remindRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the way a timer runs `wink remind` again and again
+	for i := 0; i < 3; i++ {
+		if err := a.sendReminders(peopleapi.Auth{}, rules); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(notified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Fields(string(data)), []string{remind.KindMaxDaily}; !reflect.DeepEqual(got, want) {
+		t.Errorf("notified %v, want %v", got, want)
+	}
+
+	// another day starts over
+	a.writeSentReminders(sentReminders{Date: "2000-01-01", Kinds: []string{remind.KindMaxDaily}})
+	if err := a.sendReminders(peopleapi.Auth{}, rules); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(notified); len(strings.Fields(string(data))) != 2 {
+		t.Errorf("notified %q, want the reminder again on another day", data)
+	}
+}
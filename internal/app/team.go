@@ -0,0 +1,116 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+	"github.com/harnyk/wink/internal/ui"
+)
+
+const (
+	teamFormatText = "text"
+	teamFormatCSV  = "csv"
+	teamFormatJSON = "json"
+)
+
+// teamEmployees returns the employee IDs of --employees, or of the named team of the config
+func (a *app) teamEmployees(employees string, team string) ([]string, error) {
+	var employeeIDs []string
+
+	switch {
+	case employees != "" && team != "":
+		return nil, fmt.Errorf("--employees and --team can't be used together")
+	case employees != "":
+		for _, id := range strings.Split(employees, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				employeeIDs = append(employeeIDs, id)
+			}
+		}
+	case team != "":
+		var ok bool
+		employeeIDs, ok = a.settings.Config.Teams[team]
+		if !ok {
+			var names []string
+			for name := range a.settings.Config.Teams {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown team %q, the teams are: %s", team, strings.Join(names, ", "))
+		}
+	default:
+		return nil, fmt.Errorf("use --employees or --team")
+	}
+
+	if len(employeeIDs) == 0 {
+		return nil, fmt.Errorf("no employees to report on")
+	}
+
+	return employeeIDs, nil
+}
+
+func (a *app) doTeamReport(timeStart, timeEnd time.Time, employeeIDs []string, format string, output string) error {
+	switch format {
+	case teamFormatText, teamFormatCSV, teamFormatJSON:
+	default:
+		return fmt.Errorf("unknown format %q, use %s, %s or %s", format, teamFormatText, teamFormatCSV, teamFormatJSON)
+	}
+
+	if err := a.requirePeopleHR("team report"); err != nil {
+		return err
+	}
+
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return err
+	}
+
+	// the API key has to be an admin one to read the timesheets of others
+	clientFor := func(employeeID string) peopleapi.Client {
+		return peopleapi.NewClient(peopleapi.Auth{APIKey: authData.APIKey, EmployeeID: employeeID}, a.peopleHROptions()...)
+	}
+
+	progress := ui.NewProgress("Fetching team timesheets")
+	members := peopleapi.FetchTeamTimesheets(clientFor, employeeIDs, timeStart, timeEnd, peopleapi.FetchOptions{
+		Workers:    fetchWorkers,
+		Retries:    fetchRetries,
+		RetryDelay: fetchRetryDelay,
+		Progress:   progress.Update,
+	})
+	progress.Done()
+
+	summaries := report.SummarizeTeam(members, time.Now())
+
+	var data []byte
+	switch format {
+	case teamFormatCSV:
+		data, err = report.RenderTeamReportCSV(summaries)
+	case teamFormatJSON:
+		data, err = report.RenderTeamReportJSON(summaries)
+	default:
+		data = []byte(report.RenderTeamReport(timeStart, timeEnd, summaries, a.renderOptions(reportOptions{})))
+	}
+	if err != nil {
+		return err
+	}
+
+	if output != "" {
+		return writeReportFile(output, data)
+	}
+
+	if format == teamFormatText {
+		fmt.Println()
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Print(string(data))
+	if format == teamFormatJSON {
+		fmt.Println()
+	}
+
+	return nil
+}
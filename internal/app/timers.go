@@ -0,0 +1,150 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/timers"
+)
+
+// timerEnv are the variables passed on to the units,
+// so that they use the same wink files
+var timerEnv = []string{"WINK_HOME", "WINK_DEFAULTS", "XDG_CONFIG_HOME"}
+
+func (a *app) doInstallTimers() error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("systemd timers are only supported on Linux")
+	}
+
+	if a.settings.Config.Auth.PasswordCommand == "" {
+		return fmt.Errorf("timers run unattended, set auth.password_command first")
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	opts := timers.Options{
+		Executable: executable,
+	}
+
+	for _, name := range timerEnv {
+		if value := os.Getenv(name); value != "" {
+			opts.Env = append(opts.Env, name+"="+value)
+		}
+	}
+
+	// the reminder timer is installed when there is something to remind about
+	if _, err := a.remindRules(); err == nil {
+		opts.RemindInterval = time.Duration(a.settings.Config.Remind.Interval)
+	}
+
+	if autoCheckout := a.settings.Config.Timers.AutoCheckout; autoCheckout != "" {
+		if opts.AutoCheckout, err = config.ClockTime(autoCheckout); err != nil {
+			return err
+		}
+		if opts.Workdays, err = config.ParseWeekdays(a.settings.Config.Remind.Workdays); err != nil {
+			return err
+		}
+	}
+
+	units := timers.Units(opts)
+	if len(units) == 0 {
+		return fmt.Errorf("nothing to install, set timers.auto_checkout or the remind.* settings")
+	}
+
+	dir, err := timers.Dir()
+	if err != nil {
+		return err
+	}
+
+	// units left from an earlier install with other settings
+	if _, err := a.disableTimers(dir); err != nil {
+		return err
+	}
+
+	if err := timers.Write(dir, units); err != nil {
+		return err
+	}
+
+	for _, unit := range units {
+		fmt.Println("Written " + unit.Name)
+	}
+
+	if err := systemctl("daemon-reload"); err != nil {
+		return err
+	}
+
+	for _, unit := range units {
+		if filepath.Ext(unit.Name) == ".timer" {
+			if err := systemctl("enable", "--now", unit.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	printSuccess("Timers installed")
+
+	return nil
+}
+
+func (a *app) doUninstallTimers() error {
+	dir, err := timers.Dir()
+	if err != nil {
+		return err
+	}
+
+	removed, err := a.disableTimers(dir)
+	if err != nil {
+		return err
+	}
+
+	if len(removed) == 0 {
+		fmt.Println("No timers installed")
+		return nil
+	}
+
+	for _, name := range removed {
+		fmt.Println("Removed " + name)
+	}
+
+	if err := systemctl("daemon-reload"); err != nil {
+		return err
+	}
+
+	printSuccess("Timers uninstalled")
+
+	return nil
+}
+
+// disableTimers stops and removes the installed units
+func (a *app) disableTimers(dir string) ([]string, error) {
+	for _, name := range timers.Names() {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil && filepath.Ext(name) == ".timer" {
+			if err := systemctl("disable", "--now", name); err != nil {
+				fmt.Println(color.YellowString("WARNING: %s", err))
+			}
+		}
+	}
+
+	return timers.Remove(dir)
+}
+
+func systemctl(args ...string) error {
+	cmd := exec.Command("systemctl", append([]string{"--user"}, args...)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("systemctl --user %v failed: %w", args, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/harnyk/wink/internal/config"
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+)
+
+func (a *app) doWhoami() error {
+	authData, err := a.authPrompt.Get()
+	if err != nil {
+		return err
+	}
+
+	employee, err := a.newClient(authData).GetEmployee()
+	if err != nil {
+		return err
+	}
+
+	printEmployee(authData.EmployeeID, employee)
+
+	return nil
+}
+
+func printEmployee(employeeID string, employee *peopleapi.Employee) {
+	fmt.Printf("Employee ID : %s\n", employeeID)
+	fmt.Printf("Name        : %s\n", employee.Name())
+	printEmployeeField("Email       : ", employee.EmailID.DisplayValue)
+	printEmployeeField("Job role    : ", employee.JobRole.DisplayValue)
+	printEmployeeField("Department  : ", employee.Department.DisplayValue)
+	printEmployeeField("Location    : ", employee.Location.DisplayValue)
+	printEmployeeField("Manager     : ", employee.ReportsTo.DisplayValue)
+	if weekly := employee.WeeklyHours(); weekly > 0 {
+		fmt.Printf("Contracted  : %s a week\n", report.FormatDuration(weekly))
+	}
+}
+
+func printEmployeeField(label string, value string) {
+	if value == "" {
+		value = color.New(color.Faint).Sprint("-")
+	}
+	fmt.Println(label + value)
+}
+
+// seedDailyTarget sets report.daily_target from the contracted hours,
+// unless a target is configured already
+func (a *app) seedDailyTarget(employee *peopleapi.Employee) {
+	if a.settings.Config.Report.DailyTarget > 0 {
+		return
+	}
+
+	workdays, err := config.ParseWeekdays(a.settings.Config.Remind.Workdays)
+	if err != nil {
+		return
+	}
+
+	target := employee.DailyTarget(len(workdays))
+	if target == 0 {
+		return
+	}
+
+	if err := config.Set(a.settings.Files.User, "report.daily_target", target.String()); err != nil {
+		fmt.Println(color.YellowString("WARNING: Could not set the daily target: %s", err))
+		return
+	}
+
+	fmt.Printf("Daily target set to %s from your contracted hours\n", report.FormatDuration(target))
+}
@@ -0,0 +1,145 @@
+package auditlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Entry is a single write wink sent to PeopleHR
+type Entry struct {
+	// Timestamp is the NTP-corrected time the request was sent at,
+	// or the wall clock when the NTP offset is unknown
+	Timestamp time.Time `json:"timestamp"`
+	// WallClock is the system clock at the time the request was sent
+	WallClock time.Time `json:"wall_clock"`
+	// NTPOffset is how far the system clock was ahead of NTP time, in seconds
+	NTPOffset  *float64 `json:"ntp_offset"`
+	Profile    string   `json:"profile"`
+	EmployeeID string   `json:"employee_id"`
+	Action     string   `json:"action"`
+	Date       string   `json:"date"`
+	Slot       string   `json:"slot"`
+	Time       string   `json:"time"`
+	Response   Response `json:"response"`
+}
+
+// Response is what the server answered to the write
+type Response struct {
+	Status  uint32 `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+	// Error is the error wink got while sending the request, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Filter selects entries of the log. Zero fields don't filter.
+type Filter struct {
+	// Since and Until limit the timesheet date, inclusive
+	Since      time.Time
+	Until      time.Time
+	Action     string
+	Slot       string
+	EmployeeID string
+}
+
+func (f Filter) matches(entry Entry) bool {
+	if f.Action != "" && !strings.EqualFold(f.Action, entry.Action) {
+		return false
+	}
+	if f.Slot != "" && !strings.EqualFold(f.Slot, entry.Slot) {
+		return false
+	}
+	if f.EmployeeID != "" && f.EmployeeID != entry.EmployeeID {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Date < f.Since.Format("2006-01-02") {
+		return false
+	}
+	if !f.Until.IsZero() && entry.Date > f.Until.Format("2006-01-02") {
+		return false
+	}
+	return true
+}
+
+// Log is an append-only journal of the writes wink performed,
+// stored as JSON lines
+type Log interface {
+	Append(entry Entry) error
+	Read(filter Filter) ([]Entry, error)
+}
+
+func NewLog(fileName string) Log {
+	return &log{
+		fileName: fileName,
+	}
+}
+
+type log struct {
+	fileName string
+}
+
+func (l *log) Append(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(l.fileName), 0700)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (l *log) Read(filter Filter) ([]Entry, error) {
+	entries := []Entry{}
+
+	f, err := os.Open(l.fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, err
+		}
+
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
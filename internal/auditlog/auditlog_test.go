@@ -0,0 +1,57 @@
+package auditlog_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/auditlog"
+)
+
+func TestLog(t *testing.T) {
+	log := auditlog.NewLog(filepath.Join(t.TempDir(), "wink", "audit.log"))
+
+	entries := []auditlog.Entry{
+		{Action: "CreateNewTimesheet", Date: "2023-03-01", Slot: "TimeIn1", Time: "09:00"},
+		{Action: "UpdateTimesheet", Date: "2023-03-01", Slot: "TimeOut1", Time: "17:00"},
+		{Action: "CreateNewTimesheet", Date: "2023-03-02", Slot: "TimeIn1", Time: "09:30"},
+	}
+	for _, entry := range entries {
+		if err := log.Append(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter auditlog.Filter
+		want   []string
+	}{
+		{name: "all", filter: auditlog.Filter{}, want: []string{"09:00", "17:00", "09:30"}},
+		{name: "by action", filter: auditlog.Filter{Action: "updatetimesheet"}, want: []string{"17:00"}},
+		{name: "by slot", filter: auditlog.Filter{Slot: "TimeIn1"}, want: []string{"09:00", "09:30"}},
+		{
+			name:   "by date",
+			filter: auditlog.Filter{Since: time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC), Until: time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC)},
+			want:   []string{"09:30"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := log.Read(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			times := []string{}
+			for _, entry := range got {
+				times = append(times, entry.Time)
+			}
+
+			if !reflect.DeepEqual(times, tt.want) {
+				t.Errorf("Read() got = %v, want %v", times, tt.want)
+			}
+		})
+	}
+}
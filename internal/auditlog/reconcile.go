@@ -0,0 +1,89 @@
+package auditlog
+
+import (
+	"sort"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+type DiscrepancyKind string
+
+const (
+	// DiscrepancyChanged - the server holds a different value than wink wrote
+	DiscrepancyChanged DiscrepancyKind = "changed"
+	// DiscrepancyRemoved - wink wrote the slot, but it is empty on the server
+	DiscrepancyRemoved DiscrepancyKind = "removed"
+	// DiscrepancyAdded - the slot is set on the server, but wink never wrote it
+	DiscrepancyAdded DiscrepancyKind = "added"
+)
+
+// Discrepancy is a slot where the server timesheet differs from the audit log
+type Discrepancy struct {
+	Kind   DiscrepancyKind `json:"kind"`
+	Date   string          `json:"date"`
+	Slot   string          `json:"slot"`
+	Local  string          `json:"local"`
+	Server string          `json:"server"`
+}
+
+// Reconcile compares the successful writes of the log with the timesheets
+// currently returned by the server. Both are expected to cover the same range.
+func Reconcile(entries []Entry, timeSheets []peopleapi.TimeSheet) []Discrepancy {
+	// the latest successful write of every slot wins
+	local := make(map[string]map[string]string)
+	for _, entry := range entries {
+		if entry.Response.Error != "" || entry.Response.IsError {
+			continue
+		}
+		// project entries and other writes are not attendance slots
+		if peopleapi.SlotIndex(entry.Slot) < 0 {
+			continue
+		}
+		if local[entry.Date] == nil {
+			local[entry.Date] = make(map[string]string)
+		}
+		local[entry.Date][entry.Slot] = entry.Time
+	}
+
+	server := make(map[string]map[string]string)
+	for i := range timeSheets {
+		slots := make(map[string]string)
+		for _, action := range peopleapi.TimeSheetToActionsList(&timeSheets[i]) {
+			slots[action.Slot] = action.Time
+		}
+		server[timeSheets[i].TimesheetDate] = slots
+	}
+
+	discrepancies := []Discrepancy{}
+
+	for date, slots := range local {
+		for slot, localTime := range slots {
+			serverTime, ok := server[date][slot]
+			switch {
+			case !ok && localTime == "":
+				// the slot was cleared, e.g. by compacting a full timesheet
+			case !ok:
+				discrepancies = append(discrepancies, Discrepancy{Kind: DiscrepancyRemoved, Date: date, Slot: slot, Local: localTime})
+			case !peopleapi.SameClock(localTime, serverTime):
+				discrepancies = append(discrepancies, Discrepancy{Kind: DiscrepancyChanged, Date: date, Slot: slot, Local: localTime, Server: serverTime})
+			}
+		}
+	}
+
+	for date, slots := range server {
+		for slot, serverTime := range slots {
+			if _, ok := local[date][slot]; !ok {
+				discrepancies = append(discrepancies, Discrepancy{Kind: DiscrepancyAdded, Date: date, Slot: slot, Server: serverTime})
+			}
+		}
+	}
+
+	sort.Slice(discrepancies, func(i, j int) bool {
+		if discrepancies[i].Date != discrepancies[j].Date {
+			return discrepancies[i].Date < discrepancies[j].Date
+		}
+		return peopleapi.SlotIndex(discrepancies[i].Slot) < peopleapi.SlotIndex(discrepancies[j].Slot)
+	})
+
+	return discrepancies
+}
@@ -0,0 +1,39 @@
+package auditlog_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/harnyk/wink/internal/auditlog"
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+func TestReconcile(t *testing.T) {
+	entries := []auditlog.Entry{
+		{Date: "2023-03-01", Slot: "TimeIn1", Time: "09:00"},
+		{Date: "2023-03-01", Slot: "TimeOut1", Time: "12:00"},
+		{Date: "2023-03-01", Slot: "TimeIn2", Time: "13:00"},
+		{Date: "2023-03-01", Slot: "TimeOut2", Time: "17:00", Response: auditlog.Response{Error: "timeout"}},
+		{Date: "2023-03-02", Slot: "TimeIn1", Time: "08:00"},
+		{Date: "2023-03-02", Slot: "TimeIn1", Time: "08:30"},
+		{Date: "2023-03-02", Slot: "TimeOut1", Time: "09:00"},
+		{Date: "2023-03-02", Slot: "TimeOut1", Time: ""},
+		{Date: "2023-03-02", Action: peopleapi.ActionAddProjectTimesheet, Slot: "Acme/Dev", Time: "01:30"},
+	}
+
+	timeSheets := []peopleapi.TimeSheet{
+		{TimesheetDate: "2023-03-01", TimeIn1: "09:00:00", TimeOut1: "12:15:00", TimeOut2: "17:00:00"},
+		{TimesheetDate: "2023-03-02", TimeIn1: "08:30:00"},
+	}
+
+	got := auditlog.Reconcile(entries, timeSheets)
+	want := []auditlog.Discrepancy{
+		{Kind: auditlog.DiscrepancyChanged, Date: "2023-03-01", Slot: "TimeOut1", Local: "12:00", Server: "12:15:00"},
+		{Kind: auditlog.DiscrepancyRemoved, Date: "2023-03-01", Slot: "TimeIn2", Local: "13:00"},
+		{Kind: auditlog.DiscrepancyAdded, Date: "2023-03-01", Slot: "TimeOut2", Server: "17:00:00"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconcile() got = %v, want %v", got, want)
+	}
+}
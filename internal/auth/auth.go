@@ -2,25 +2,40 @@ package auth
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/harnyk/wink/internal/cryptostore"
 	"github.com/harnyk/wink/internal/entities"
 	api "github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/shell"
 	"github.com/harnyk/wink/internal/ui"
 )
 
 type AuthPrompt interface {
 	Get() (api.Auth, error)
+	// Password returns the password the secrets were unlocked with,
+	// it is also the key of the other encrypted local files
+	Password() (string, error)
 }
 
+// PasswordEnv is the environment variable the password can be passed in
+// when wink runs unattended
+const PasswordEnv = "WINK_PASSWORD"
+
 type authPrompt struct {
-	cachedAuth     api.Auth
-	configFileName string
+	cachedAuth      api.Auth
+	cachedPassword  string
+	configFileName  string
+	passwordCommand string
 }
 
-func NewAuthPrompt(configFileName string) AuthPrompt {
+// NewAuthPrompt returns a prompt which takes the password from $WINK_PASSWORD,
+// from the output of passwordCommand, or asks for it, in this order
+func NewAuthPrompt(configFileName string, passwordCommand string) AuthPrompt {
 	return &authPrompt{
-		configFileName: configFileName,
+		configFileName:  configFileName,
+		passwordCommand: passwordCommand,
 	}
 }
 
@@ -30,9 +45,8 @@ func (a *authPrompt) Get() (api.Auth, error) {
 	}
 
 	store := cryptostore.NewCryptoStore[entities.Secrets](a.configFileName)
-	u := ui.NewUI()
 
-	password, err := u.AskPassword("Please enter the password:")
+	password, interactive, err := a.readPassword()
 	if err != nil {
 		return api.Auth{}, err
 	}
@@ -42,13 +56,64 @@ func (a *authPrompt) Get() (api.Auth, error) {
 		return api.Auth{}, err
 	}
 
-	fmt.Println("Credentials loaded")
-	fmt.Printf("Employee ID: %s\n", record.EmployeeID)
+	if interactive {
+		fmt.Println("Credentials loaded")
+		fmt.Printf("Employee ID: %s\n", record.EmployeeID)
+	}
 
 	a.cachedAuth = api.Auth{
 		APIKey:     record.APIKey,
 		EmployeeID: record.EmployeeID,
 	}
+	a.cachedPassword = password
 
 	return a.cachedAuth, nil
 }
+
+func (a *authPrompt) Password() (string, error) {
+	if _, err := a.Get(); err != nil {
+		return "", err
+	}
+
+	return a.cachedPassword, nil
+}
+
+// readPassword returns the password and whether the user was asked for it
+func (a *authPrompt) readPassword() (string, bool, error) {
+	if password := os.Getenv(PasswordEnv); password != "" {
+		return password, false, nil
+	}
+
+	if a.passwordCommand != "" {
+		cmd := shell.Command(a.passwordCommand)
+		cmd.Stderr = os.Stderr
+
+		out, err := cmd.Output()
+		if err != nil {
+			return "", false, fmt.Errorf("password command failed: %w", err)
+		}
+
+		return strings.TrimRight(string(out), "\r\n"), false, nil
+	}
+
+	password, err := ui.NewUI().AskPassword("Please enter the password:")
+	return password, true, err
+}
+
+type localAuth struct {
+	employeeID string
+}
+
+// NewLocalAuth returns a prompt for backends without accounts,
+// it never asks for anything
+func NewLocalAuth(employeeID string) AuthPrompt {
+	return &localAuth{employeeID: employeeID}
+}
+
+func (a *localAuth) Get() (api.Auth, error) {
+	return api.Auth{EmployeeID: a.employeeID}, nil
+}
+
+func (a *localAuth) Password() (string, error) {
+	return "", nil
+}
@@ -0,0 +1,470 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Duration is a time.Duration written as a string in the config file, e.g. "10m"
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Config is the non-secret configuration of wink
+type Config struct {
+	// DefaultsFile is a team-wide defaults file layered below the user config
+	DefaultsFile string `toml:"defaults_file"`
+	// Backend is where the timesheets are kept: peoplehr or local
+	Backend string `toml:"backend"`
+	// LocalFile is the JSON lines file of the local backend, <wink home>/timesheets.jsonl if empty
+	LocalFile string `toml:"local_file"`
+	// ClockTolerance is how far the system clock may drift from NTP time
+	// before wink warns about it
+	ClockTolerance Duration `toml:"clock_tolerance"`
+	NTPServer      string   `toml:"ntp_server"`
+	// Overnight is what a check-out after midnight does with yesterday's open interval:
+	// previous-day closes it on yesterday's timesheet, split closes it at midnight
+	// and adds the rest to today's timesheet, off refuses the check-out
+	Overnight string `toml:"overnight"`
+	// OvernightUntil is the latest clock time of a check-out which closes yesterday's interval, e.g. 06:00
+	OvernightUntil string `toml:"overnight_until"`
+	// OvernightMaxShift is the longest shift a check-out after midnight may close,
+	// a longer one was most likely left open by mistake
+	OvernightMaxShift Duration `toml:"overnight_max_shift"`
+
+	Auth      AuthConfig      `toml:"auth"`
+	EasterEgg EasterEggConfig `toml:"easteregg"`
+	Report    ReportConfig    `toml:"report"`
+	Hooks     HooksConfig     `toml:"hooks"`
+	Remind    RemindConfig    `toml:"remind"`
+	Timers    TimersConfig    `toml:"timers"`
+	Prompt    PromptConfig    `toml:"prompt"`
+	Holiday   HolidayConfig   `toml:"holiday"`
+	Compact   CompactConfig   `toml:"compact"`
+	// Teams are named lists of employee IDs for `wink team report --team`
+	Teams map[string][]string `toml:"teams"`
+}
+
+// CompactConfig is how a full timesheet makes room for the next check-in or check-out
+type CompactConfig struct {
+	// Mode is prompt, auto or off
+	Mode string `toml:"mode"`
+	// MaxBreak is the break length from which on breaks are kept, shorter ones are merged away.
+	// Zero-length intervals are always dropped.
+	MaxBreak Duration `toml:"max_break"`
+}
+
+type HolidayConfig struct {
+	// AllowanceDays is the yearly holiday allowance, used by `wink holiday balance`
+	AllowanceDays float64 `toml:"allowance_days"`
+	// YearStart is the first day of the holiday year, MM-DD
+	YearStart string `toml:"year_start"`
+}
+
+type PromptConfig struct {
+	// Format is the text/template of `wink prompt`, empty for the built-in one
+	Format string `toml:"format"`
+}
+
+type AuthConfig struct {
+	// PasswordCommand prints the password of the secrets file, for unattended runs,
+	// e.g. "pass show wink"
+	PasswordCommand string `toml:"password_command"`
+}
+
+// HooksConfig are the executables or shell snippets run around check-in and check-out.
+// A failing pre hook aborts the action.
+type HooksConfig struct {
+	PreIn   []string `toml:"pre_in"`
+	PostIn  []string `toml:"post_in"`
+	PreOut  []string `toml:"pre_out"`
+	PostOut []string `toml:"post_out"`
+}
+
+type EasterEggConfig struct {
+	// RudeProbability is the chance of a rude check-in/out phrase, 0..1
+	RudeProbability float64 `toml:"rude_probability"`
+}
+
+type ReportConfig struct {
+	// DefaultRange is the range used without --start: month, week, last-month or last-week
+	DefaultRange string `toml:"default_range"`
+	// DateFormat is the Go layout of the --start and --end flags
+	DateFormat string `toml:"date_format"`
+	// DisplayDateFormat is the Go layout of the report range heading
+	DisplayDateFormat string `toml:"display_date_format"`
+	// DayFormat is the Go layout of the date of every report line
+	DayFormat string `toml:"day_format"`
+	// Compliance is the compliance preset checked by default, empty to disable
+	Compliance string `toml:"compliance"`
+	// ComplianceRules override the thresholds of the compliance preset
+	ComplianceRules ComplianceRulesConfig `toml:"compliance_rules"`
+	// Holidays shows approved holidays as non-working days
+	Holidays bool `toml:"holidays"`
+	// DailyTarget is the working time expected per day, 0 for none
+	DailyTarget Duration `toml:"daily_target"`
+}
+
+// ComplianceRulesConfig overrides thresholds of the compliance preset, an empty value keeps the preset's.
+// Durations are like 11h, 0s disables the check.
+type ComplianceRulesConfig struct {
+	// Breaks are the minimum break steps as AFTER:MIN_BREAK, e.g. 6h:30m
+	Breaks []string `toml:"breaks"`
+	// MinBreakLength is the shortest break that counts towards the break steps
+	MinBreakLength string `toml:"min_break_length"`
+	// MaxDaily is the maximum time worked per day
+	MaxDaily string `toml:"max_daily"`
+	// MinRest is the minimum rest between the last check-out of a day and the first check-in of the next
+	MinRest string `toml:"min_rest"`
+}
+
+// IsSet tells whether any threshold is overridden
+func (c ComplianceRulesConfig) IsSet() bool {
+	return len(c.Breaks) > 0 || c.MinBreakLength != "" || c.MaxDaily != "" || c.MinRest != ""
+}
+
+// BreakStep parses a minimum break step like "6h:30m", a break of 30m once the time worked exceeds 6h
+func BreakStep(s string) (after time.Duration, minBreak time.Duration, err error) {
+	afterStr, minBreakStr, ok := strings.Cut(s, ":")
+
+	after, afterErr := time.ParseDuration(strings.TrimSpace(afterStr))
+	minBreak, minBreakErr := time.ParseDuration(strings.TrimSpace(minBreakStr))
+	if !ok || afterErr != nil || minBreakErr != nil || after <= 0 || minBreak <= 0 {
+		return 0, 0, fmt.Errorf("invalid break step %q, want AFTER:MIN_BREAK like 6h:30m", s)
+	}
+
+	return after, minBreak, nil
+}
+
+// RemindConfig are the checks of `wink remind`
+type RemindConfig struct {
+	// CheckoutAfter is the clock time after which being still checked in is reminded, empty to disable
+	CheckoutAfter string `toml:"checkout_after"`
+	// CheckinBy is the clock time by which a workday should have a check-in, empty to disable
+	CheckinBy string `toml:"checkin_by"`
+	// MaxDaily is the daily working time which is reminded once crossed, 0 to disable
+	MaxDaily Duration `toml:"max_daily"`
+	// Workdays are the days CheckinBy is checked on: mon, tue, wed, thu, fri, sat, sun
+	Workdays []string `toml:"workdays"`
+	// Notify is the command notifications are sent through, with the text in $WINK_MESSAGE.
+	// The terminal bell is used if empty.
+	Notify string `toml:"notify"`
+	// Interval is how often `wink remind --daemon` checks
+	Interval Duration `toml:"interval"`
+}
+
+// Year returns the first and the last day of the holiday year t falls in
+func (c HolidayConfig) Year(t time.Time) (time.Time, time.Time) {
+	yearStart, err := time.Parse("01-02", c.YearStart)
+	if err != nil {
+		yearStart = time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	start := time.Date(t.Year(), yearStart.Month(), yearStart.Day(), 0, 0, 0, 0, t.Location())
+	if start.After(t) {
+		start = start.AddDate(-1, 0, 0)
+	}
+
+	return start, start.AddDate(1, 0, -1)
+}
+
+// TimersConfig are the scheduled actions `wink install-timers` sets up
+type TimersConfig struct {
+	// AutoCheckout is the clock time of an automatic check-out on remind.workdays, empty to disable
+	AutoCheckout string `toml:"auto_checkout"`
+}
+
+// ClockTime parses a clock time setting like "18:30" into the time since midnight
+func ClockTime(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseWeekdays parses short weekday names like "mon"
+func ParseWeekdays(names []string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range names {
+		day, ok := weekdays[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", name)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+const (
+	BackendPeopleHR = "peoplehr"
+	BackendLocal    = "local"
+)
+
+const (
+	OvernightPreviousDay = "previous-day"
+	OvernightSplit       = "split"
+	OvernightOff         = "off"
+)
+
+const (
+	CompactPrompt = "prompt"
+	CompactAuto   = "auto"
+	CompactOff    = "off"
+)
+
+const (
+	RangeMonth     = "month"
+	RangeWeek      = "week"
+	RangeLastMonth = "last-month"
+	RangeLastWeek  = "last-week"
+)
+
+// Default returns the built-in configuration
+func Default() Config {
+	return Config{
+		Backend:           BackendPeopleHR,
+		ClockTolerance:    Duration(10 * time.Minute),
+		NTPServer:         "pool.ntp.org",
+		Overnight:         OvernightPreviousDay,
+		OvernightUntil:    "06:00",
+		OvernightMaxShift: Duration(16 * time.Hour),
+		EasterEgg: EasterEggConfig{
+			RudeProbability: 0.5,
+		},
+		Report: ReportConfig{
+			DefaultRange:      RangeMonth,
+			DateFormat:        "2006-01-02",
+			DisplayDateFormat: "02-Jan-2006",
+			DayFormat:         "02-Jan",
+			Holidays:          true,
+		},
+		Holiday: HolidayConfig{
+			YearStart: "01-01",
+		},
+		Compact: CompactConfig{
+			Mode:     CompactPrompt,
+			MaxBreak: Duration(5 * time.Minute),
+		},
+		Remind: RemindConfig{
+			Workdays: []string{"mon", "tue", "wed", "thu", "fri"},
+			Interval: Duration(5 * time.Minute),
+		},
+	}
+}
+
+// Validate checks the values which can't be checked by their type
+func (c Config) Validate() error {
+	if c.EasterEgg.RudeProbability < 0 || c.EasterEgg.RudeProbability > 1 {
+		return fmt.Errorf("easteregg.rude_probability must be between 0 and 1")
+	}
+
+	if c.Backend != BackendPeopleHR && c.Backend != BackendLocal {
+		return fmt.Errorf("backend must be %s or %s", BackendPeopleHR, BackendLocal)
+	}
+
+	switch c.Overnight {
+	case OvernightPreviousDay, OvernightSplit, OvernightOff:
+	default:
+		return fmt.Errorf("overnight must be one of %s, %s, %s", OvernightPreviousDay, OvernightSplit, OvernightOff)
+	}
+
+	if _, err := ClockTime(c.OvernightUntil); err != nil {
+		return fmt.Errorf("overnight_until must be a clock time like 06:00")
+	}
+
+	if c.OvernightMaxShift <= 0 {
+		return fmt.Errorf("overnight_max_shift must be positive")
+	}
+
+	switch c.Report.DefaultRange {
+	case RangeMonth, RangeWeek, RangeLastMonth, RangeLastWeek:
+	default:
+		return fmt.Errorf("report.default_range must be one of %s, %s, %s, %s",
+			RangeMonth, RangeWeek, RangeLastMonth, RangeLastWeek)
+	}
+
+	if c.ClockTolerance < 0 {
+		return fmt.Errorf("clock_tolerance must not be negative")
+	}
+
+	if c.Report.DailyTarget < 0 {
+		return fmt.Errorf("report.daily_target must not be negative")
+	}
+
+	for _, step := range c.Report.ComplianceRules.Breaks {
+		if _, _, err := BreakStep(step); err != nil {
+			return fmt.Errorf("report.compliance_rules.breaks: %w", err)
+		}
+	}
+
+	for key, value := range map[string]string{
+		"report.compliance_rules.min_break_length": c.Report.ComplianceRules.MinBreakLength,
+		"report.compliance_rules.max_daily":        c.Report.ComplianceRules.MaxDaily,
+		"report.compliance_rules.min_rest":         c.Report.ComplianceRules.MinRest,
+	} {
+		if d, err := time.ParseDuration(value); value != "" && (err != nil || d < 0) {
+			return fmt.Errorf("%s must be a duration like 11h, 0s to disable", key)
+		}
+	}
+
+	for key, value := range map[string]string{
+		"remind.checkout_after": c.Remind.CheckoutAfter,
+		"remind.checkin_by":     c.Remind.CheckinBy,
+		"timers.auto_checkout":  c.Timers.AutoCheckout,
+	} {
+		if _, err := ClockTime(value); value != "" && err != nil {
+			return fmt.Errorf("%s must be a clock time like 18:30", key)
+		}
+	}
+
+	if _, err := ParseWeekdays(c.Remind.Workdays); err != nil {
+		return fmt.Errorf("remind.workdays: %w", err)
+	}
+
+	if _, err := time.Parse("01-02", c.Holiday.YearStart); err != nil {
+		return fmt.Errorf("holiday.year_start must be a date like 04-01")
+	}
+
+	if c.Holiday.AllowanceDays < 0 {
+		return fmt.Errorf("holiday.allowance_days must not be negative")
+	}
+
+	switch c.Compact.Mode {
+	case CompactPrompt, CompactAuto, CompactOff:
+	default:
+		return fmt.Errorf("compact.mode must be one of %s, %s, %s", CompactPrompt, CompactAuto, CompactOff)
+	}
+
+	if c.Compact.MaxBreak < 0 {
+		return fmt.Errorf("compact.max_break must not be negative")
+	}
+
+	if c.Remind.Interval < Duration(time.Minute) {
+		return fmt.Errorf("remind.interval must be at least 1m")
+	}
+
+	return nil
+}
+
+// Files are the config files the configuration is layered from
+type Files struct {
+	// User is the per-user config file, the one `wink config set` writes
+	User string
+	// Defaults is the team-wide defaults file, empty if there is none
+	Defaults string
+}
+
+// HomeDir returns the directory wink keeps its files in:
+// $WINK_HOME if set, ~/.wink otherwise
+func HomeDir() (string, error) {
+	if dir := os.Getenv("WINK_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".wink"), nil
+}
+
+// UserFile returns the path of the user config file:
+// $WINK_HOME/config.toml, $XDG_CONFIG_HOME/wink/config.toml or ~/.wink/config.toml
+func UserFile() (string, error) {
+	if os.Getenv("WINK_HOME") == "" {
+		if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+			return filepath.Join(xdg, "wink", "config.toml"), nil
+		}
+	}
+
+	dir, err := HomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// ErrInvalid is wrapped by the Load error about a setting with an invalid value,
+// the configuration is returned all the same so that it can be shown and fixed
+var ErrInvalid = errors.New("invalid setting")
+
+// Load layers the built-in defaults, the team defaults file and the user file.
+// The team defaults file is taken from $WINK_DEFAULTS, or from the
+// defaults_file of the user config. Missing files are skipped.
+// An invalid setting is blamed on the user file if it is invalid on its own, else on the team defaults file.
+func Load(userFile string) (Config, Files, error) {
+	cfg := Default()
+	files := Files{User: userFile}
+
+	user := Default()
+	if err := decodeFile(userFile, &user); err != nil {
+		return cfg, files, err
+	}
+
+	defaultsSource := "WINK_DEFAULTS"
+	files.Defaults = os.Getenv("WINK_DEFAULTS")
+	if files.Defaults == "" {
+		defaultsSource = "defaults_file"
+		files.Defaults = user.DefaultsFile
+	}
+
+	if files.Defaults != "" {
+		if err := decodeFile(files.Defaults, &cfg); err != nil {
+			return cfg, files, err
+		}
+	}
+
+	if err := decodeFile(userFile, &cfg); err != nil {
+		return cfg, files, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		if userErr := user.Validate(); userErr != nil || files.Defaults == "" {
+			return cfg, files, fmt.Errorf("%w in the user config file %s: %w", ErrInvalid, userFile, err)
+		}
+		return cfg, files, fmt.Errorf("%w in the team defaults file %s, set by %s: %w", ErrInvalid, files.Defaults, defaultsSource, err)
+	}
+
+	return cfg, files, nil
+}
+
+// decodeFile decodes the file on top of the values already in cfg,
+// a missing file is not an error
+func decodeFile(fileName string, v interface{}) error {
+	_, err := toml.DecodeFile(fileName, v)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", fileName, err)
+	}
+	return nil
+}
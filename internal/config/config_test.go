@@ -0,0 +1,238 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/config"
+)
+
+func TestLoadLayers(t *testing.T) {
+	dir := t.TempDir()
+	defaultsFile := filepath.Join(dir, "team.toml")
+	userFile := filepath.Join(dir, "config.toml")
+
+	err := os.WriteFile(defaultsFile, []byte(`
+ntp_server = "ntp.example.com"
+clock_tolerance = "2m"
+
+[report]
+compliance = "de"
+default_range = "week"
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(userFile, []byte(`
+defaults_file = "`+defaultsFile+`"
+
+[report]
+default_range = "last-month"
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("WINK_DEFAULTS", "")
+
+	cfg, files, err := config.Load(userFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if files.Defaults != defaultsFile {
+		t.Errorf("Load() defaults file = %q, want %q", files.Defaults, defaultsFile)
+	}
+	if cfg.NTPServer != "ntp.example.com" {
+		t.Errorf("Load() ntp_server = %q, want the team default", cfg.NTPServer)
+	}
+	if time.Duration(cfg.ClockTolerance) != 2*time.Minute {
+		t.Errorf("Load() clock_tolerance = %v, want 2m", time.Duration(cfg.ClockTolerance))
+	}
+	if cfg.Report.Compliance != "de" {
+		t.Errorf("Load() report.compliance = %q, want the team default", cfg.Report.Compliance)
+	}
+	if cfg.Report.DefaultRange != config.RangeLastMonth {
+		t.Errorf("Load() report.default_range = %q, want the user value", cfg.Report.DefaultRange)
+	}
+	if cfg.EasterEgg.RudeProbability != 0.5 {
+		t.Errorf("Load() easteregg.rude_probability = %v, want the built-in default", cfg.EasterEgg.RudeProbability)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults string
+		user     string
+		wantErr  string
+	}{
+		{name: "user file", user: `overnight = "never"`, wantErr: "user config file"},
+		{name: "team defaults file", defaults: `overnight = "never"`, user: `ntp_server = "ntp.example.com"`, wantErr: "team defaults file"},
+		{name: "team default overridden", defaults: `overnight = "never"`, user: `overnight = "off"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			defaultsFile := filepath.Join(dir, "team.toml")
+			userFile := filepath.Join(dir, "config.toml")
+
+			if err := os.WriteFile(defaultsFile, []byte(tt.defaults), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(userFile, []byte(tt.user), 0600); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("WINK_DEFAULTS", defaultsFile)
+
+			cfg, _, err := config.Load(userFile)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, config.ErrInvalid) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %v naming the %s", err, config.ErrInvalid, tt.wantErr)
+			}
+			// the settings are there all the same, to be shown and fixed
+			if cfg.Overnight != "never" {
+				t.Errorf("Load() overnight = %q, want the invalid value", cfg.Overnight)
+			}
+		})
+	}
+}
+
+func TestSetWithInvalidFile(t *testing.T) {
+	userFile := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(userFile, []byte("overnight = \"never\"\n[report]\ndefault_range = \"year\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// one invalid setting is fixed at a time
+	if err := config.Set(userFile, "overnight", "off"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := config.Set(userFile, "report.default_range", "week"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if _, _, err := config.Load(userFile); err != nil {
+		t.Errorf("Load() error = %v, want the fixed file to load", err)
+	}
+}
+
+func TestSetGet(t *testing.T) {
+	userFile := filepath.Join(t.TempDir(), "wink", "config.toml")
+
+	tests := []struct {
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{key: "clock_tolerance", value: "90s", want: "1m30s"},
+		{key: "easteregg.rude_probability", value: "0.1", want: "0.1"},
+		{key: "report.day_format", value: "Jan 02", want: "Jan 02"},
+		{key: "hooks.pre_in", value: `notify-send "in, now"`, want: `notify-send "in, now"`},
+		{key: "hooks.post_in", value: `a.sh, echo 'x,y'`, want: `a.sh,echo 'x,y'`},
+		{key: "report.compliance_rules.breaks", value: "6h:30m, 9h:45m", want: "6h:30m,9h:45m"},
+		{key: "report.compliance_rules.min_rest", value: "12h", want: "12h"},
+		{key: "easteregg.rude_probability", value: "2", wantErr: true},
+		{key: "report.compliance_rules.breaks", value: "30m after 6h", wantErr: true},
+		{key: "report.compliance_rules.max_daily", value: "-1h", wantErr: true},
+		{key: "report.default_range", value: "year", wantErr: true},
+		{key: "clock_tolerance", value: "soon", wantErr: true},
+		{key: "no_such_key", value: "1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			err := config.Set(userFile, tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			cfg, _, err := config.Load(userFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := config.Get(cfg, tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Get() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// earlier values survive later writes
+	cfg, _, err := config.Load(userFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(cfg.ClockTolerance) != 90*time.Second {
+		t.Errorf("clock_tolerance = %v, want 1m30s", time.Duration(cfg.ClockTolerance))
+	}
+}
+
+func TestHolidayYear(t *testing.T) {
+	tests := []struct {
+		yearStart string
+		now       time.Time
+		wantStart string
+		wantEnd   string
+	}{
+		{yearStart: "01-01", now: time.Date(2023, 5, 2, 12, 0, 0, 0, time.UTC), wantStart: "2023-01-01", wantEnd: "2023-12-31"},
+		{yearStart: "04-01", now: time.Date(2023, 5, 2, 12, 0, 0, 0, time.UTC), wantStart: "2023-04-01", wantEnd: "2024-03-31"},
+		{yearStart: "04-01", now: time.Date(2023, 2, 2, 12, 0, 0, 0, time.UTC), wantStart: "2022-04-01", wantEnd: "2023-03-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.yearStart+" "+tt.now.Format("2006-01-02"), func(t *testing.T) {
+			start, end := config.HolidayConfig{YearStart: tt.yearStart}.Year(tt.now)
+			if start.Format("2006-01-02") != tt.wantStart || end.Format("2006-01-02") != tt.wantEnd {
+				t.Errorf("Year() = %s - %s, want %s - %s", start.Format("2006-01-02"), end.Format("2006-01-02"), tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestLoadTeams(t *testing.T) {
+	userFile := filepath.Join(t.TempDir(), "config.toml")
+
+	err := os.WriteFile(userFile, []byte(`
+[teams]
+  backend = ["PW1", "PW2"]
+  design = ["PW7"]
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("WINK_DEFAULTS", "")
+
+	cfg, _, err := config.Load(userFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := config.Get(cfg, "teams")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "backend=PW1,PW2; design=PW7"
+	if got != want {
+		t.Errorf("Get() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+var durationType = reflect.TypeOf(Duration(0))
+
+// Keys returns the dotted names of all settings, e.g. "report.default_range"
+func Keys() []string {
+	var keys []string
+	walkKeys(reflect.TypeOf(Config{}), "", func(key string, _ []int) {
+		keys = append(keys, key)
+	})
+	return keys
+}
+
+func walkKeys(t reflect.Type, prefix string, fn func(key string, index []int)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := prefix + strings.Split(field.Tag.Get("toml"), ",")[0]
+
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			walkKeys(field.Type, name+".", func(key string, index []int) {
+				fn(key, append([]int{i}, index...))
+			})
+			continue
+		}
+
+		fn(name, []int{i})
+	}
+}
+
+func fieldIndex(key string) ([]int, error) {
+	var found []int
+	walkKeys(reflect.TypeOf(Config{}), "", func(k string, index []int) {
+		if k == key {
+			found = index
+		}
+	})
+	if found == nil {
+		return nil, fmt.Errorf("unknown config key %q", key)
+	}
+	return found, nil
+}
+
+// Get returns the value of the setting formatted as a string
+func Get(cfg Config, key string) (string, error) {
+	index, err := fieldIndex(key)
+	if err != nil {
+		return "", err
+	}
+
+	return formatValue(reflect.ValueOf(cfg).FieldByIndex(index)), nil
+}
+
+func formatValue(v reflect.Value) string {
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatValue(v.Index(i))
+		}
+		return strings.Join(items, ",")
+	case v.Kind() == reflect.Map:
+		items := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			items = append(items, key.String()+"="+formatValue(v.MapIndex(key)))
+		}
+		sort.Strings(items)
+		return strings.Join(items, "; ")
+	case v.Kind() == reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// parseValue converts the string to the type of the setting,
+// in the form the TOML encoder writes it
+func parseValue(t reflect.Type, value string) (interface{}, error) {
+	switch {
+	case t == durationType:
+		if _, err := time.ParseDuration(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case t.Kind() == reflect.String:
+		return value, nil
+	case t.Kind() == reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	case t.Kind() == reflect.Int:
+		return strconv.Atoi(value)
+	case t.Kind() == reflect.Bool:
+		return strconv.ParseBool(value)
+	case t.Kind() == reflect.Slice:
+		items := []interface{}{}
+		for _, item := range splitList(value) {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			parsed, err := parseValue(t.Elem(), item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, parsed)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unsupported config value type %s", t)
+}
+
+// splitList splits a list value on the commas outside of quotes, keeping the quotes,
+// so a hook like `notify-send "in, now"` stays one command
+func splitList(value string) []string {
+	var items []string
+	var quote rune
+	start := 0
+
+	for i, r := range value {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ',':
+			items = append(items, value[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(items, value[start:])
+}
+
+// Set writes the setting to the user config file, keeping the other settings
+func Set(userFile string, key string, value string) error {
+	index, err := fieldIndex(key)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := parseValue(reflect.TypeOf(Config{}).FieldByIndex(index).Type, value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	data := map[string]interface{}{}
+	if _, err := toml.DecodeFile(userFile, &data); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	setPath(data, key, parsed)
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(data); err != nil {
+		return err
+	}
+
+	// make sure the result is still a config file, and the value a valid one, before writing it.
+	// Other invalid settings of the file don't stop the write, so that they can be fixed one by one.
+	check := Default()
+	if _, err := toml.Decode(buf.String(), &check); err != nil {
+		return err
+	}
+	if err := validateValue(key, parsed); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(userFile), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(userFile, buf.Bytes(), 0600)
+}
+
+// setPath sets the dotted key in the tables, creating the missing ones
+func setPath(data map[string]interface{}, key string, value interface{}) {
+	path := strings.Split(key, ".")
+	table := data
+	for _, name := range path[:len(path)-1] {
+		sub, ok := table[name].(map[string]interface{})
+		if !ok {
+			sub = map[string]interface{}{}
+			table[name] = sub
+		}
+		table = sub
+	}
+	table[path[len(path)-1]] = value
+}
+
+// validateValue checks the value of the setting on top of the built-in defaults
+func validateValue(key string, value interface{}) error {
+	data := map[string]interface{}{}
+	setPath(data, key, value)
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(data); err != nil {
+		return err
+	}
+
+	check := Default()
+	if _, err := toml.Decode(buf.String(), &check); err != nil {
+		return err
+	}
+	return check.Validate()
+}
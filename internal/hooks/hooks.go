@@ -0,0 +1,67 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/harnyk/wink/internal/shell"
+)
+
+const (
+	PhasePre  = "pre"
+	PhasePost = "post"
+)
+
+// EnvGuard is set for every hook, wink commands run by a hook don't run hooks again
+const EnvGuard = "WINK_HOOK"
+
+// Context is what a hook gets to know about the action, through environment variables
+type Context struct {
+	Phase      string
+	Action     string
+	Date       time.Time
+	Time       string
+	Slot       string
+	Profile    string
+	TodayTotal time.Duration
+}
+
+func (c Context) env() []string {
+	total := c.TodayTotal.Round(time.Minute)
+
+	return []string{
+		EnvGuard + "=1",
+		"WINK_PHASE=" + c.Phase,
+		"WINK_ACTION=" + c.Action,
+		"WINK_DATE=" + c.Date.Format("2006-01-02"),
+		"WINK_TIME=" + c.Time,
+		"WINK_SLOT=" + c.Slot,
+		"WINK_PROFILE=" + c.Profile,
+		fmt.Sprintf("WINK_TODAY_TOTAL=%dh%02dm", int(total.Hours()), int(total.Minutes())%60),
+		fmt.Sprintf("WINK_TODAY_TOTAL_MINUTES=%d", int(total.Minutes())),
+	}
+}
+
+// IsNested reports whether wink itself was started by a hook
+func IsNested() bool {
+	return os.Getenv(EnvGuard) != ""
+}
+
+// Run runs the commands one by one through the shell, stopping at the first failure.
+// A command is either a path to an executable or a shell snippet.
+func Run(commands []string, ctx Context) error {
+	for _, command := range commands {
+		cmd := shell.Command(command)
+		cmd.Env = append(os.Environ(), ctx.env()...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s-%s hook %q failed: %w", ctx.Phase, ctx.Action, command, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package hooks_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/hooks"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run through sh in this test")
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+	ctx := hooks.Context{
+		Phase:      hooks.PhasePost,
+		Action:     "out",
+		Date:       time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC),
+		Time:       "17:30",
+		Slot:       "TimeOut1",
+		TodayTotal: 8*time.Hour + 5*time.Minute,
+	}
+
+	tests := []struct {
+		name     string
+		commands []string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "env is passed",
+			commands: []string{`echo "$WINK_ACTION $WINK_DATE $WINK_TIME $WINK_SLOT $WINK_TODAY_TOTAL $WINK_TODAY_TOTAL_MINUTES $WINK_HOOK" > ` + out},
+			want:     "out 2023-05-02 17:30 TimeOut1 8h05m 485 1\n",
+		},
+		{
+			name:     "stops at the first failure",
+			commands: []string{"exit 1", "echo ran > " + out},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(out)
+
+			err := hooks.Run(tt.commands, ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			got, _ := os.ReadFile(out)
+			if strings.TrimSpace(string(got)) != strings.TrimSpace(tt.want) {
+				t.Errorf("Run() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
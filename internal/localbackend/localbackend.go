@@ -0,0 +1,256 @@
+package localbackend
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+// EmployeeID is the employee of the local backend, it has no accounts
+const EmployeeID = "local"
+
+// NewClient returns a client keeping the attendance timesheets in a JSON lines file,
+// one timesheet per line in the PeopleHR wire format, ordered by date.
+// Projects, holidays, absences and employee details are not supported.
+func NewClient(fileName string, opts ...Option) peopleapi.Client {
+	c := &client{
+		fileName: fileName,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Option configures the local client
+type Option func(*client)
+
+// WithDryRun prints the changed timesheet to w instead of saving it
+func WithDryRun(w io.Writer) Option {
+	return func(c *client) {
+		c.dryRun = w
+	}
+}
+
+type client struct {
+	fileName string
+	now      func() time.Time
+	// dryRun gets the changes instead of the file, nil to save them
+	dryRun io.Writer
+}
+
+func (c *client) CreateNewTimesheet(timeStr string) (*peopleapi.EditResponse, error) {
+	return c.edit(func(timeSheets map[string]peopleapi.TimeSheet, date string, clock string) error {
+		if _, ok := timeSheets[date]; ok {
+			return fmt.Errorf("there is a timesheet for %s already", date)
+		}
+		timeSheets[date] = peopleapi.TimeSheet{TimesheetDate: date, TimeIn1: clock}
+		return nil
+	}, timeStr)
+}
+
+func (c *client) CheckInOut(slot string, timeStr string) (*peopleapi.EditResponse, error) {
+	if peopleapi.SlotIndex(slot) < 0 {
+		return nil, fmt.Errorf("unknown slot %q", slot)
+	}
+
+	return c.edit(func(timeSheets map[string]peopleapi.TimeSheet, date string, clock string) error {
+		timeSheet, ok := timeSheets[date]
+		if !ok {
+			return fmt.Errorf("there is no timesheet for %s", date)
+		}
+		if err := timeSheet.SetSlot(slot, clock); err != nil {
+			return err
+		}
+		timeSheets[date] = timeSheet
+		return nil
+	}, timeStr)
+}
+
+func (c *client) UpdateTimesheet(timeSheet peopleapi.TimeSheet) (*peopleapi.EditResponse, error) {
+	timeSheets, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := timeSheets[timeSheet.TimesheetDate]; !ok {
+		err := fmt.Errorf("there is no timesheet for %s", timeSheet.TimesheetDate)
+		return &peopleapi.EditResponse{Message: err.Error(), IsError: true}, err
+	}
+	timeSheets[timeSheet.TimesheetDate] = timeSheet
+
+	return c.commit(timeSheets, timeSheet.TimesheetDate)
+}
+
+// edit applies the change to today's timesheet at the given or the current time
+func (c *client) edit(change func(timeSheets map[string]peopleapi.TimeSheet, date string, clock string) error, timeStr string) (*peopleapi.EditResponse, error) {
+	now := c.now()
+
+	clock := now.Format("15:04:05")
+	if timeStr != "" {
+		if !peopleapi.IsValidTime(timeStr) {
+			return nil, fmt.Errorf("invalid time format")
+		}
+		clock = timeStr + ":00"
+	}
+
+	timeSheets, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	date := now.Format("2006-01-02")
+	if err := change(timeSheets, date, clock); err != nil {
+		return &peopleapi.EditResponse{Message: err.Error(), IsError: true}, err
+	}
+
+	return c.commit(timeSheets, date)
+}
+
+// commit saves the timesheets, or shows the changed one on a dry run
+func (c *client) commit(timeSheets map[string]peopleapi.TimeSheet, date string) (*peopleapi.EditResponse, error) {
+	if c.dryRun != nil {
+		timeSheet := timeSheets[date]
+		fmt.Fprintf(c.dryRun, "Dry run, not saved to %s: %s", c.fileName, date)
+		for _, action := range peopleapi.TimeSheetToActionsList(&timeSheet) {
+			fmt.Fprintf(c.dryRun, " %s=%s", action.Slot, action.Time)
+		}
+		fmt.Fprintln(c.dryRun)
+		return &peopleapi.EditResponse{Message: "Dry run"}, nil
+	}
+
+	if err := c.save(timeSheets); err != nil {
+		return nil, err
+	}
+
+	return &peopleapi.EditResponse{Message: "Saved locally"}, nil
+}
+
+func (c *client) GetTimesheet(startDate time.Time, endDate time.Time) (*peopleapi.GetTimesheetResponse, error) {
+	if startDate.IsZero() {
+		startDate = c.now()
+	}
+	if endDate.IsZero() {
+		endDate = c.now()
+	}
+
+	timeSheets, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := startDate.Format("2006-01-02"), endDate.Format("2006-01-02")
+
+	response := &peopleapi.GetTimesheetResponse{Result: []peopleapi.TimeSheet{}}
+	for _, timeSheet := range sorted(timeSheets) {
+		if timeSheet.TimesheetDate >= start && timeSheet.TimesheetDate <= end {
+			response.Result = append(response.Result, timeSheet)
+		}
+	}
+
+	return response, nil
+}
+
+func (c *client) AddProjectTimesheet(entry peopleapi.ProjectTimesheet) (*peopleapi.EditResponse, error) {
+	return nil, peopleapi.ErrNotSupported
+}
+
+func (c *client) GetProjectTimesheet(startDate time.Time, endDate time.Time) (*peopleapi.GetProjectTimesheetResponse, error) {
+	return nil, peopleapi.ErrNotSupported
+}
+
+func (c *client) GetHolidays(startDate time.Time, endDate time.Time) (*peopleapi.GetHolidayResponse, error) {
+	return nil, peopleapi.ErrNotSupported
+}
+
+func (c *client) RequestHoliday(request peopleapi.HolidayRequest) (*peopleapi.EditResponse, error) {
+	return nil, peopleapi.ErrNotSupported
+}
+
+func (c *client) GetAbsences(startDate time.Time, endDate time.Time) (*peopleapi.GetAbsenceResponse, error) {
+	return nil, peopleapi.ErrNotSupported
+}
+
+func (c *client) GetEmployee() (*peopleapi.Employee, error) {
+	return nil, peopleapi.ErrNotSupported
+}
+
+// load reads the timesheets by date, a missing file has none
+func (c *client) load() (map[string]peopleapi.TimeSheet, error) {
+	timeSheets := make(map[string]peopleapi.TimeSheet)
+
+	file, err := os.Open(c.fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return timeSheets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var timeSheet peopleapi.TimeSheet
+		if err := json.Unmarshal(scanner.Bytes(), &timeSheet); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", c.fileName, line, err)
+		}
+		timeSheets[timeSheet.TimesheetDate] = timeSheet
+	}
+
+	return timeSheets, scanner.Err()
+}
+
+// save rewrites the file through a temporary one, so that it is never left half-written
+func (c *client) save(timeSheets map[string]peopleapi.TimeSheet) error {
+	if err := os.MkdirAll(filepath.Dir(c.fileName), 0700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.fileName), filepath.Base(c.fileName)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	encoder := json.NewEncoder(tmp)
+	for _, timeSheet := range sorted(timeSheets) {
+		if err := encoder.Encode(timeSheet); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.fileName)
+}
+
+func sorted(timeSheets map[string]peopleapi.TimeSheet) []peopleapi.TimeSheet {
+	result := make([]peopleapi.TimeSheet, 0, len(timeSheets))
+	for _, timeSheet := range timeSheets {
+		result = append(result, timeSheet)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TimesheetDate < result[j].TimesheetDate
+	})
+
+	return result
+}
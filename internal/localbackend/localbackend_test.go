@@ -0,0 +1,65 @@
+package localbackend
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+func TestClient(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "timesheets.jsonl")
+
+	now := time.Date(2023, 5, 2, 9, 5, 30, 0, time.UTC)
+	c := &client{fileName: fileName, now: func() time.Time { return now }}
+
+	steps := []struct {
+		name    string
+		write   func() (*peopleapi.EditResponse, error)
+		wantErr bool
+	}{
+		{name: "check in", write: func() (*peopleapi.EditResponse, error) { return c.CreateNewTimesheet("") }},
+		{name: "check in twice", write: func() (*peopleapi.EditResponse, error) { return c.CreateNewTimesheet("") }, wantErr: true},
+		{name: "check out", write: func() (*peopleapi.EditResponse, error) { return c.CheckInOut("TimeOut1", "12:00") }},
+		{name: "check in again", write: func() (*peopleapi.EditResponse, error) { return c.CheckInOut("TimeIn2", "13:00") }},
+		{name: "invalid time", write: func() (*peopleapi.EditResponse, error) { return c.CheckInOut("TimeOut2", "5pm") }, wantErr: true},
+		{name: "unknown slot", write: func() (*peopleapi.EditResponse, error) { return c.CheckInOut("TimeOut16", "17:00") }, wantErr: true},
+	}
+	for _, step := range steps {
+		if _, err := step.write(); (err != nil) != step.wantErr {
+			t.Fatalf("%s: error = %v, wantErr %v", step.name, err, step.wantErr)
+		}
+	}
+
+	// a day before, not in the range
+	now = now.AddDate(0, 0, -1)
+	if _, err := c.CreateNewTimesheet("08:00"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.GetTimesheet(time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC), time.Date(2023, 5, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := peopleapi.TimeSheet{TimesheetDate: "2023-05-02", TimeIn1: "09:05:30", TimeOut1: "12:00:00", TimeIn2: "13:00:00"}
+	if len(resp.Result) != 1 || resp.Result[0] != want {
+		t.Errorf("GetTimesheet() = %+v, want %+v", resp.Result, want)
+	}
+
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("file has %d lines, want one per date", lines)
+	}
+
+	if _, err := c.GetEmployee(); !errors.Is(err, peopleapi.ErrNotSupported) {
+		t.Errorf("GetEmployee() error = %v, want ErrNotSupported", err)
+	}
+}
@@ -0,0 +1,70 @@
+package peopleapi
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidAPIKey   = errors.New("the API key is invalid")
+	ErrUnknownEmployee = errors.New("the employee ID is unknown")
+	ErrNetwork         = errors.New("could not reach PeopleHR")
+	ErrNoPermission    = errors.New("the API key has no permission for it")
+)
+
+// CheckCredentials reads today's timesheet, which is all wink needs the credentials for,
+// and then fetches the employee the client is authenticated as. The employee is nil
+// when the API key may not read employee details, the credentials are fine all the same.
+// The error wraps ErrInvalidAPIKey, ErrUnknownEmployee, ErrNoPermission or ErrNetwork when it can be told apart.
+func CheckCredentials(client Client) (*Employee, error) {
+	if _, err := client.GetTimesheet(time.Time{}, time.Time{}); err != nil {
+		return nil, classifyCredentialsError(err)
+	}
+
+	employee, err := client.GetEmployee()
+	if err != nil {
+		if err := classifyCredentialsError(err); errors.Is(err, ErrUnknownEmployee) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return employee, nil
+}
+
+func classifyCredentialsError(err error) error {
+	if errors.Is(err, ErrUnknownEmployee) {
+		return err
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) &&
+		(httpErr.StatusCode == http.StatusUnauthorized || httpErr.StatusCode == http.StatusForbidden) {
+		return fmt.Errorf("%w: %s", ErrInvalidAPIKey, err)
+	}
+
+	var serverErr *ServerError
+	if errors.As(err, &serverErr) {
+		message := strings.ToLower(strings.ReplaceAll(serverErr.Message, " ", ""))
+		switch {
+		// a key lacking a permission is a valid key, whatever else the message says
+		case strings.Contains(message, "permission"):
+			return fmt.Errorf("%w: %s", ErrNoPermission, serverErr.Message)
+		case strings.Contains(message, "apikey"):
+			return fmt.Errorf("%w: %s", ErrInvalidAPIKey, serverErr.Message)
+		case strings.Contains(message, "employee"):
+			return fmt.Errorf("%w: %s", ErrUnknownEmployee, serverErr.Message)
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return fmt.Errorf("%w: %s", ErrNetwork, err)
+	}
+
+	return err
+}
@@ -0,0 +1,78 @@
+package peopleapi
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestClassifyCredentialsError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "invalid key message", err: &ServerError{Message: "Invalid Api Key"}, want: ErrInvalidAPIKey},
+		{name: "unauthorized", err: &HTTPError{StatusCode: 401, Status: "401 Unauthorized"}, want: ErrInvalidAPIKey},
+		{name: "no permission message", err: &ServerError{Message: "Api Key does not have permission to access this action"}, want: ErrNoPermission},
+		{name: "unknown employee message", err: &ServerError{Message: "Employee not found"}, want: ErrUnknownEmployee},
+		{name: "no employee returned", err: ErrUnknownEmployee, want: ErrUnknownEmployee},
+		{name: "network", err: &net.DNSError{Err: "no such host", Name: "api.peoplehr.net"}, want: ErrNetwork},
+		{name: "other", err: &HTTPError{StatusCode: 500, Status: "500 Internal Server Error"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyCredentialsError(tt.err)
+			for _, sentinel := range []error{ErrInvalidAPIKey, ErrUnknownEmployee, ErrNoPermission, ErrNetwork} {
+				if errors.Is(got, sentinel) != (sentinel == tt.want) {
+					t.Errorf("classifyCredentialsError() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// credentialsClient answers the reads CheckCredentials makes
+type credentialsClient struct {
+	Client
+	timesheetErr error
+	employee     *Employee
+	employeeErr  error
+}
+
+func (c credentialsClient) GetTimesheet(startDate time.Time, endDate time.Time) (*GetTimesheetResponse, error) {
+	return &GetTimesheetResponse{}, c.timesheetErr
+}
+
+func (c credentialsClient) GetEmployee() (*Employee, error) {
+	return c.employee, c.employeeErr
+}
+
+func TestCheckCredentials(t *testing.T) {
+	noPermission := &ServerError{Message: "Api Key does not have permission to access this action"}
+
+	tests := []struct {
+		name         string
+		client       credentialsClient
+		wantEmployee bool
+		wantErr      error
+	}{
+		{name: "valid", client: credentialsClient{employee: &Employee{}}, wantEmployee: true},
+		{name: "timesheet permission only", client: credentialsClient{employeeErr: noPermission}},
+		{name: "no timesheet permission", client: credentialsClient{timesheetErr: noPermission}, wantErr: ErrNoPermission},
+		{name: "invalid key", client: credentialsClient{timesheetErr: &ServerError{Message: "Invalid Api Key"}}, wantErr: ErrInvalidAPIKey},
+		{name: "unknown employee", client: credentialsClient{employeeErr: ErrUnknownEmployee}, wantErr: ErrUnknownEmployee},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employee, err := CheckCredentials(tt.client)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("CheckCredentials() error = %v, want %v", err, tt.wantErr)
+			}
+			if (employee != nil) != tt.wantEmployee {
+				t.Errorf("CheckCredentials() employee = %v, want one %v", employee, tt.wantEmployee)
+			}
+		})
+	}
+}
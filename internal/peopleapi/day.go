@@ -0,0 +1,267 @@
+package peopleapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSequence means check-ins and check-outs of a timesheet don't alternate
+var ErrInvalidSequence = errors.New("invalid sequence of check-ins and check-outs")
+
+// Interval is a check-in and the check-out following it, as clock times since midnight
+type Interval struct {
+	// Pair is the number N of the TimeInN/TimeOutN slots the interval is kept in, 1..SlotCount
+	Pair int
+	In   time.Duration
+	Out  time.Duration
+	// Open is set while there is no check-out yet, Out is zero then
+	Open bool
+}
+
+// InSlot returns the name of the check-in slot, e.g. TimeIn1
+func (i Interval) InSlot() string {
+	return fmt.Sprintf("TimeIn%d", i.Pair)
+}
+
+// OutSlot returns the name of the check-out slot, e.g. TimeOut1
+func (i Interval) OutSlot() string {
+	return fmt.Sprintf("TimeOut%d", i.Pair)
+}
+
+// End returns the check-out as time since midnight of the day of the check-in.
+// A check-out earlier than the check-in is on the next day, the interval crosses midnight.
+func (i Interval) End() time.Duration {
+	if i.Out < i.In {
+		return i.Out + 24*time.Hour
+	}
+	return i.Out
+}
+
+// Duration returns the length of a closed interval, 0 while it is open
+func (i Interval) Duration() time.Duration {
+	if i.Open {
+		return 0
+	}
+	return i.End() - i.In
+}
+
+// Day is the typed form of a TimeSheet: the work intervals of a date in slot order
+type Day struct {
+	// Date is zero for a timesheet without a date, e.g. before the first check-in
+	Date      time.Time
+	Intervals []Interval
+}
+
+// ParseDay converts a timesheet into a Day.
+// On ErrInvalidSequence the intervals before the offending slot are returned along with the error.
+func ParseDay(timeSheet TimeSheet) (Day, error) {
+	var day Day
+
+	if timeSheet.TimesheetDate != "" {
+		date, err := ParseDate(timeSheet.TimesheetDate)
+		if err != nil {
+			return day, err
+		}
+		day.Date = date
+	}
+
+	slots := timeSheet.slots()
+
+	for pair := 1; pair <= SlotCount; pair++ {
+		in, out := *slots[2*pair-2], *slots[2*pair-1]
+		if in == "" && out == "" {
+			continue
+		}
+
+		interval := Interval{Pair: pair}
+
+		if day.IsOpen() {
+			return day, fmt.Errorf("%w: %s after an open interval", ErrInvalidSequence, interval.InSlot())
+		}
+		if in == "" {
+			return day, fmt.Errorf("%w: %s without %s", ErrInvalidSequence, interval.OutSlot(), interval.InSlot())
+		}
+
+		var err error
+		if interval.In, err = ParseClock(in); err != nil {
+			return day, fmt.Errorf("%s: %w", interval.InSlot(), err)
+		}
+
+		if out == "" {
+			interval.Open = true
+		} else if interval.Out, err = ParseClock(out); err != nil {
+			return day, fmt.Errorf("%s: %w", interval.OutSlot(), err)
+		}
+
+		day.Intervals = append(day.Intervals, interval)
+	}
+
+	return day, nil
+}
+
+// IsOpen reports whether the day is checked in
+func (d Day) IsOpen() bool {
+	return len(d.Intervals) > 0 && d.Intervals[len(d.Intervals)-1].Open
+}
+
+// Worked returns the total of the closed intervals
+func (d Day) Worked() time.Duration {
+	var worked time.Duration
+	for _, interval := range d.Intervals {
+		worked += interval.Duration()
+	}
+	return worked
+}
+
+// NextSlot returns the slot the next check-in or check-out goes to, "" if the timesheet is full
+func (d Day) NextSlot() string {
+	if len(d.Intervals) == 0 {
+		return Interval{Pair: 1}.InSlot()
+	}
+
+	last := d.Intervals[len(d.Intervals)-1]
+	if last.Open {
+		return last.OutSlot()
+	}
+	if last.Pair >= SlotCount {
+		return ""
+	}
+	return Interval{Pair: last.Pair + 1}.InSlot()
+}
+
+// Compact drops zero-length intervals and merges intervals separated by breaks shorter than maxBreak,
+// the remaining intervals are renumbered from the first pair on
+func (d Day) Compact(maxBreak time.Duration) Day {
+	compacted := Day{Date: d.Date}
+
+	for _, interval := range d.Intervals {
+		if !interval.Open && interval.Out == interval.In {
+			continue
+		}
+
+		if n := len(compacted.Intervals); n > 0 && interval.In-compacted.Intervals[n-1].End() < maxBreak {
+			last := &compacted.Intervals[n-1]
+			if interval.Open || interval.End() > last.End() {
+				last.Out = interval.Out
+			}
+			last.Open = interval.Open
+			continue
+		}
+
+		interval.Pair = len(compacted.Intervals) + 1
+		compacted.Intervals = append(compacted.Intervals, interval)
+	}
+
+	return compacted
+}
+
+// Validate checks that the intervals fit into the slots of a timesheet:
+// pairs in ascending order within 1..SlotCount, and only the last interval open
+func (d Day) Validate() error {
+	pair := 0
+	for i, interval := range d.Intervals {
+		if interval.Pair <= pair || interval.Pair > SlotCount {
+			return fmt.Errorf("interval %d: slot pair %d is out of order", i+1, interval.Pair)
+		}
+		if interval.Open && i != len(d.Intervals)-1 {
+			return fmt.Errorf("%w: %s after an open interval", ErrInvalidSequence, d.Intervals[i+1].InSlot())
+		}
+		pair = interval.Pair
+	}
+	return nil
+}
+
+// TimeSheet converts the day back into a timesheet
+func (d Day) TimeSheet() (TimeSheet, error) {
+	var timeSheet TimeSheet
+
+	if err := d.Validate(); err != nil {
+		return timeSheet, err
+	}
+
+	if !d.Date.IsZero() {
+		timeSheet.TimesheetDate = d.Date.Format("2006-01-02")
+	}
+
+	slots := timeSheet.slots()
+	for _, interval := range d.Intervals {
+		*slots[2*interval.Pair-2] = FormatClock(interval.In)
+		if !interval.Open {
+			*slots[2*interval.Pair-1] = FormatClock(interval.Out)
+		}
+	}
+
+	return timeSheet, nil
+}
+
+// clockLayouts are the slot formats seen in timesheets: wink writes 15:04, PeopleHR returns 15:04:05,
+// and entries made in the web UI or imported may have a 12-hour clock or a date
+var clockLayouts = []string{
+	"15:04:05",
+	"15:04",
+	"3:04:05 PM",
+	"3:04:05PM",
+	"3:04 PM",
+	"3:04PM",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// dateLayouts are the formats a timesheet date may come in, PeopleHR sometimes adds a midnight time
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// ParseDate parses a timesheet date like 2006-01-02 or 2006-01-02T00:00:00 into midnight UTC of the date
+func ParseDate(s string) (time.Time, error) {
+	normalized := strings.TrimSpace(s)
+
+	for _, layout := range dateLayouts {
+		t, err := time.Parse(layout, normalized)
+		if err != nil {
+			continue
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// ParseClock parses a slot value like 15:04:05 or 15:04 into the time since midnight
+func ParseClock(s string) (time.Duration, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(s))
+
+	for _, layout := range clockLayouts {
+		t, err := time.Parse(layout, normalized)
+		if err != nil {
+			continue
+		}
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+	}
+
+	return 0, fmt.Errorf("invalid time %q", s)
+}
+
+// SameClock compares slot values to the minute whatever their format,
+// wink writes 15:04 while PeopleHR returns 15:04:05
+func SameClock(a string, b string) bool {
+	clockA, errA := ParseClock(a)
+	clockB, errB := ParseClock(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return clockA.Truncate(time.Minute) == clockB.Truncate(time.Minute)
+}
+
+// FormatClock formats the time since midnight as a slot value, 15:04:05
+func FormatClock(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
@@ -0,0 +1,297 @@
+package peopleapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDay(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeSheet    TimeSheet
+		want         []Interval
+		wantSequence bool
+		wantErr      bool
+		wantNext     string
+	}{
+		{
+			name:     "empty",
+			want:     nil,
+			wantNext: "TimeIn1",
+		},
+		{
+			name:      "checked in",
+			timeSheet: TimeSheet{TimesheetDate: "2020-01-01", TimeIn1: "08:00:00", TimeOut1: "12:00:00", TimeIn2: "13:00:00"},
+			want: []Interval{
+				{Pair: 1, In: 8 * time.Hour, Out: 12 * time.Hour},
+				{Pair: 2, In: 13 * time.Hour, Open: true},
+			},
+			wantNext: "TimeOut2",
+		},
+		{
+			name:      "pairs with a gap",
+			timeSheet: TimeSheet{TimesheetDate: "2020-01-01", TimeIn1: "08:00:00", TimeOut1: "12:00:00", TimeIn3: "13:00:00", TimeOut3: "17:30:15"},
+			want: []Interval{
+				{Pair: 1, In: 8 * time.Hour, Out: 12 * time.Hour},
+				{Pair: 3, In: 13 * time.Hour, Out: 17*time.Hour + 30*time.Minute + 15*time.Second},
+			},
+			wantNext: "TimeIn4",
+		},
+		{
+			name:         "check-in after an open interval",
+			timeSheet:    TimeSheet{TimesheetDate: "2020-01-01", TimeIn1: "08:00:00", TimeIn2: "09:00:00"},
+			want:         []Interval{{Pair: 1, In: 8 * time.Hour, Open: true}},
+			wantSequence: true,
+		},
+		{
+			name:         "check-out without check-in",
+			timeSheet:    TimeSheet{TimesheetDate: "2020-01-01", TimeIn1: "08:00:00", TimeOut1: "12:00:00", TimeOut2: "13:00:00"},
+			want:         []Interval{{Pair: 1, In: 8 * time.Hour, Out: 12 * time.Hour}},
+			wantSequence: true,
+		},
+		{
+			name:      "full",
+			timeSheet: TimeSheet{TimeIn15: "22:00:00", TimeOut15: "23:00:00"},
+			want:      []Interval{{Pair: 15, In: 22 * time.Hour, Out: 23 * time.Hour}},
+			wantNext:  "",
+		},
+		{
+			name:      "bad time",
+			timeSheet: TimeSheet{TimesheetDate: "2020-01-01", TimeIn1: "08:XX:YY"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day, err := ParseDay(tt.timeSheet)
+			if errors.Is(err, ErrInvalidSequence) != tt.wantSequence {
+				t.Fatalf("ParseDay() error = %v, want invalid sequence %v", err, tt.wantSequence)
+			}
+			if tt.wantSequence {
+				if !reflect.DeepEqual(day.Intervals, tt.want) {
+					t.Errorf("ParseDay() intervals = %v, want %v", day.Intervals, tt.want)
+				}
+				return
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(day.Intervals, tt.want) {
+				t.Errorf("ParseDay() intervals = %v, want %v", day.Intervals, tt.want)
+			}
+			if got := day.NextSlot(); got != tt.wantNext {
+				t.Errorf("NextSlot() = %q, want %q", got, tt.wantNext)
+			}
+
+			back, err := day.TimeSheet()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back != tt.timeSheet {
+				t.Errorf("TimeSheet() = %+v, want %+v", back, tt.timeSheet)
+			}
+		})
+	}
+}
+
+func TestDayValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		day     Day
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			day:  Day{Intervals: []Interval{{Pair: 1, In: time.Hour, Out: 2 * time.Hour}, {Pair: 2, In: 3 * time.Hour, Open: true}}},
+		},
+		{
+			name:    "pairs out of order",
+			day:     Day{Intervals: []Interval{{Pair: 2, In: time.Hour, Out: 2 * time.Hour}, {Pair: 1, In: 3 * time.Hour, Out: 4 * time.Hour}}},
+			wantErr: true,
+		},
+		{
+			name:    "pair out of range",
+			day:     Day{Intervals: []Interval{{Pair: SlotCount + 1, In: time.Hour, Out: 2 * time.Hour}}},
+			wantErr: true,
+		},
+		{
+			name:    "open interval in the middle",
+			day:     Day{Intervals: []Interval{{Pair: 1, In: time.Hour, Open: true}, {Pair: 2, In: 3 * time.Hour, Out: 4 * time.Hour}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.day.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetSlot(t *testing.T) {
+	var timeSheet TimeSheet
+
+	if err := timeSheet.SetSlot("TimeOut7", "10:00:00"); err != nil {
+		t.Fatal(err)
+	}
+	if timeSheet.TimeOut7 != "10:00:00" || timeSheet.Slot("TimeOut7") != "10:00:00" {
+		t.Errorf("SetSlot() = %+v, want TimeOut7 set", timeSheet)
+	}
+	if err := timeSheet.SetSlot("TimesheetDate", "2020-01-01"); err == nil {
+		t.Errorf("SetSlot() of a non-slot field should fail")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       []Interval
+		maxBreak time.Duration
+		want     []Interval
+	}{
+		{
+			name: "short breaks are merged",
+			in: []Interval{
+				{Pair: 1, In: 8 * time.Hour, Out: 10 * time.Hour},
+				{Pair: 2, In: 10*time.Hour + 3*time.Minute, Out: 11 * time.Hour},
+				{Pair: 3, In: 12 * time.Hour, Out: 13 * time.Hour},
+			},
+			maxBreak: 5 * time.Minute,
+			want: []Interval{
+				{Pair: 1, In: 8 * time.Hour, Out: 11 * time.Hour},
+				{Pair: 2, In: 12 * time.Hour, Out: 13 * time.Hour},
+			},
+		},
+		{
+			name: "a break as long as maxBreak is kept",
+			in: []Interval{
+				{Pair: 1, In: 8 * time.Hour, Out: 10 * time.Hour},
+				{Pair: 2, In: 10*time.Hour + 5*time.Minute, Out: 11 * time.Hour},
+			},
+			maxBreak: 5 * time.Minute,
+			want: []Interval{
+				{Pair: 1, In: 8 * time.Hour, Out: 10 * time.Hour},
+				{Pair: 2, In: 10*time.Hour + 5*time.Minute, Out: 11 * time.Hour},
+			},
+		},
+		{
+			name: "zero-length intervals are dropped",
+			in: []Interval{
+				{Pair: 1, In: 8 * time.Hour, Out: 10 * time.Hour},
+				{Pair: 2, In: 11 * time.Hour, Out: 11 * time.Hour},
+				{Pair: 3, In: 12 * time.Hour, Out: 13 * time.Hour},
+			},
+			want: []Interval{
+				{Pair: 1, In: 8 * time.Hour, Out: 10 * time.Hour},
+				{Pair: 2, In: 12 * time.Hour, Out: 13 * time.Hour},
+			},
+		},
+		{
+			name: "open interval stays open",
+			in: []Interval{
+				{Pair: 1, In: 8 * time.Hour, Out: 10 * time.Hour},
+				{Pair: 2, In: 10*time.Hour + time.Minute, Open: true},
+			},
+			maxBreak: 5 * time.Minute,
+			want: []Interval{
+				{Pair: 1, In: 8 * time.Hour, Open: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Day{Intervals: tt.in}.Compact(tt.maxBreak)
+			if !reflect.DeepEqual(got.Intervals, tt.want) {
+				t.Errorf("Compact() = %v, want %v", got.Intervals, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "15:04:05", want: 15*time.Hour + 4*time.Minute + 5*time.Second},
+		{in: "15:04", want: 15*time.Hour + 4*time.Minute},
+		{in: "9:30", want: 9*time.Hour + 30*time.Minute},
+		{in: " 09:30:00 ", want: 9*time.Hour + 30*time.Minute},
+		{in: "09:30:00.000", want: 9*time.Hour + 30*time.Minute},
+		{in: "3:04 pm", want: 15*time.Hour + 4*time.Minute},
+		{in: "12:15AM", want: 15 * time.Minute},
+		{in: "2023-05-02T08:00:00", want: 8 * time.Hour},
+		{in: "25:00", wantErr: true},
+		{in: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseClock(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseClock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClockError(t *testing.T) {
+	if _, err := ParseClock(" soon"); err == nil || err.Error() != `invalid time " soon"` {
+		t.Errorf("ParseClock() error = %v, want the value as given", err)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Time
+		wantErr bool
+	}{
+		{s: "2023-05-01", want: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{s: "2023-05-01T00:00:00", want: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{s: "2023-05-01 00:00:00", want: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{s: "2023-05-01T00:00:00+02:00", want: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{s: "01/05/2023", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseDate(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameClock(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "09:00", b: "09:00:00", want: true},
+		{a: "9:00", b: "09:00:59", want: true},
+		{a: "09:00", b: "09:01:00", want: false},
+		{a: "", b: "09:00", want: false},
+		{a: "", b: "", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"="+tt.b, func(t *testing.T) {
+			if got := SameClock(tt.a, tt.b); got != tt.want {
+				t.Errorf("SameClock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
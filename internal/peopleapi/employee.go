@@ -0,0 +1,54 @@
+package peopleapi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GetEmployee returns the details of the employee the client is authenticated as
+func (c *client) GetEmployee() (*Employee, error) {
+	response := &GetEmployeeResponse{}
+
+	err := c.post("/Employee", map[string]string{
+		"APIKey":     c.auth.APIKey,
+		"EmployeeId": c.auth.EmployeeID,
+		"Action":     "GetEmployeeDetailById",
+	}, response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.IsError {
+		return nil, &ServerError{Message: response.Message}
+	}
+
+	if len(response.Result) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEmployee, c.auth.EmployeeID)
+	}
+
+	return &response.Result[0], nil
+}
+
+func (e *Employee) Name() string {
+	return strings.TrimSpace(e.FirstName.DisplayValue + " " + e.LastName.DisplayValue)
+}
+
+// WeeklyHours returns the contracted hours per week, 0 if unknown
+func (e *Employee) WeeklyHours() time.Duration {
+	hours, err := ParseHours(e.ContractedHours.DisplayValue)
+	if err != nil {
+		return 0
+	}
+	return hours
+}
+
+// DailyTarget spreads the contracted weekly hours over the working days of the week,
+// 0 if the contracted hours are unknown
+func (e *Employee) DailyTarget(workdays int) time.Duration {
+	if workdays == 0 {
+		return 0
+	}
+	return (e.WeeklyHours() / time.Duration(workdays)).Round(time.Minute)
+}
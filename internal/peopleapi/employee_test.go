@@ -0,0 +1,41 @@
+package peopleapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEmployeeDailyTarget(t *testing.T) {
+	data := `{"isError":false,"Result":[{
+		"EmployeeId":{"DisplayValue":"PW123"},
+		"FirstName":{"DisplayValue":"Alex"},
+		"LastName":{"DisplayValue":"Doe"},
+		"ContractedHoursPerWeek":{"DisplayValue":"37.5"}
+	}]}`
+
+	var response GetEmployeeResponse
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	employee := response.Result[0]
+
+	if got := employee.Name(); got != "Alex Doe" {
+		t.Errorf("Name() = %q, want %q", got, "Alex Doe")
+	}
+
+	tests := []struct {
+		workdays int
+		want     time.Duration
+	}{
+		{workdays: 5, want: 7*time.Hour + 30*time.Minute},
+		{workdays: 4, want: 9*time.Hour + 23*time.Minute},
+		{workdays: 0, want: 0},
+	}
+	for _, tt := range tests {
+		if got := employee.DailyTarget(tt.workdays); got != tt.want {
+			t.Errorf("DailyTarget(%d) = %v, want %v", tt.workdays, got, tt.want)
+		}
+	}
+}
@@ -85,9 +85,149 @@ func (gtsr *GetTimesheetResponse) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
-	// if aux.Result is an empty string or an empty object, we need to set it to an empty array
-	if string(aux.Result) == `""` || string(aux.Result) == `{}` {
-		aux.Result = []byte("[]")
+	return json.Unmarshal(normalizeResult(aux.Result), &gtsr.Result)
+}
+
+// normalizeResult turns an empty Result, which PeopleHR sends as an empty string
+// or an empty object, into an empty array
+func normalizeResult(result json.RawMessage) json.RawMessage {
+	if len(result) == 0 || string(result) == `""` || string(result) == `{}` || string(result) == "null" {
+		return []byte("[]")
+	}
+	return result
+}
+
+// ProjectTimesheet is a project time entry, the company bills clients from them
+type ProjectTimesheet struct {
+	TimesheetProjectID   json.Number `json:"TimesheetProjectId,omitempty"`
+	ProjectTimesheetDate string
+	Project              string
+	Task                 string
+	// TotalHours is the time spent, HH:MM
+	TotalHours string
+	StartTime  string
+	EndTime    string
+	Notes      string
+}
+
+type GetProjectTimesheetResponse struct {
+	IsError bool               `json:"isError"`
+	Message string             `json:"Message"`
+	Result  []ProjectTimesheet `json:"Result"`
+}
+
+func (r *GetProjectTimesheetResponse) UnmarshalJSON(data []byte) error {
+	type Alias GetProjectTimesheetResponse
+	aux := &struct {
+		Result json.RawMessage `json:"Result"`
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return json.Unmarshal(normalizeResult(aux.Result), &r.Result)
+}
+
+// Holiday is a holiday request, the dates are YYYY-MM-DD
+type Holiday struct {
+	StartDate string
+	EndDate   string
+	// Duration is in days, or hours if DurationType is "Hours"
+	Duration     string
+	DurationType string
+	// PartOfTheDay is AM or PM for a half-day holiday
+	PartOfTheDay string
+	// Status is Approved, Pending, Rejected or Cancelled
+	Status   string
+	Comments string
+}
+
+type GetHolidayResponse struct {
+	IsError bool      `json:"isError"`
+	Message string    `json:"Message"`
+	Result  []Holiday `json:"Result"`
+}
+
+func (r *GetHolidayResponse) UnmarshalJSON(data []byte) error {
+	type Alias GetHolidayResponse
+	aux := &struct {
+		Result json.RawMessage `json:"Result"`
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return json.Unmarshal(normalizeResult(aux.Result), &r.Result)
+}
+
+// Absence is a sickness or other absence record, the dates are YYYY-MM-DD
+type Absence struct {
+	StartDate string
+	EndDate   string
+	Reason    string
+	// Duration is in days
+	Duration string
+	Status   string
+}
+
+type GetAbsenceResponse struct {
+	IsError bool      `json:"isError"`
+	Message string    `json:"Message"`
+	Result  []Absence `json:"Result"`
+}
+
+func (r *GetAbsenceResponse) UnmarshalJSON(data []byte) error {
+	type Alias GetAbsenceResponse
+	aux := &struct {
+		Result json.RawMessage `json:"Result"`
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	return json.Unmarshal(normalizeResult(aux.Result), &r.Result)
+}
+
+// EmployeeField is a field of the employee record, PeopleHR wraps every value with its history
+type EmployeeField struct {
+	DisplayValue string `json:"DisplayValue"`
+}
+
+type Employee struct {
+	EmployeeID EmployeeField `json:"EmployeeId"`
+	FirstName  EmployeeField `json:"FirstName"`
+	LastName   EmployeeField `json:"LastName"`
+	EmailID    EmployeeField `json:"EmailId"`
+	Department EmployeeField `json:"Department"`
+	Location   EmployeeField `json:"Location"`
+	JobRole    EmployeeField `json:"JobRole"`
+	ReportsTo  EmployeeField `json:"ReportsTo"`
+	// ContractedHours are the contracted working hours per week
+	ContractedHours EmployeeField `json:"ContractedHoursPerWeek"`
+}
+
+type GetEmployeeResponse struct {
+	IsError bool       `json:"isError"`
+	Message string     `json:"Message"`
+	Result  []Employee `json:"Result"`
+}
+
+func (r *GetEmployeeResponse) UnmarshalJSON(data []byte) error {
+	type Alias GetEmployeeResponse
+	aux := &struct {
+		Result json.RawMessage `json:"Result"`
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
 	}
-	return json.Unmarshal(aux.Result, &gtsr.Result)
+	return json.Unmarshal(normalizeResult(aux.Result), &r.Result)
 }
@@ -0,0 +1,158 @@
+package peopleapi
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DateRange is an inclusive range of dates
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// FetchOptions control how FetchTimesheetRange splits and retries requests
+type FetchOptions struct {
+	// Workers is the number of concurrent requests, 4 if not set
+	Workers int
+	// Retries is the number of retries of a chunk on transient errors
+	Retries int
+	// RetryDelay is the delay before the first retry, doubled on every attempt
+	RetryDelay time.Duration
+	// Progress is called after every finished chunk
+	Progress func(done int, total int)
+}
+
+const defaultFetchWorkers = 4
+
+// SplitMonthly splits the range into chunks which don't cross month boundaries
+func SplitMonthly(startDate time.Time, endDate time.Time) []DateRange {
+	var chunks []DateRange
+
+	for chunkStart := startDate; !chunkStart.After(endDate); {
+		nextMonth := time.Date(chunkStart.Year(), chunkStart.Month()+1, 1, 0, 0, 0, 0, chunkStart.Location())
+		chunkEnd := nextMonth.AddDate(0, 0, -1)
+		if chunkEnd.After(endDate) {
+			chunkEnd = endDate
+		}
+
+		chunks = append(chunks, DateRange{Start: chunkStart, End: chunkEnd})
+		chunkStart = nextMonth
+	}
+
+	return chunks
+}
+
+// FetchTimesheetRange fetches the timesheets of a long range in monthly chunks
+// using a bounded pool of workers. Chunks failing with transient errors are retried.
+// The result is de-duplicated by TimesheetDate and ordered by date.
+func FetchTimesheetRange(client Client, startDate time.Time, endDate time.Time, opts FetchOptions) ([]TimeSheet, error) {
+	chunks := SplitMonthly(startDate, endDate)
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultFetchWorkers
+	}
+
+	type chunkResult struct {
+		timeSheets []TimeSheet
+		err        error
+	}
+
+	jobs := make(chan DateRange)
+	results := make(chan chunkResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				timeSheets, err := fetchChunk(client, chunk, opts)
+				results <- chunkResult{timeSheets: timeSheets, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, chunk := range chunks {
+			jobs <- chunk
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	byDate := make(map[string]TimeSheet)
+	done := 0
+	var firstErr error
+
+	for result := range results {
+		done++
+		if opts.Progress != nil {
+			opts.Progress(done, len(chunks))
+		}
+
+		if result.err != nil {
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			continue
+		}
+
+		for _, timeSheet := range result.timeSheets {
+			if _, ok := byDate[timeSheet.TimesheetDate]; !ok {
+				byDate[timeSheet.TimesheetDate] = timeSheet
+			}
+		}
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	timeSheets := make([]TimeSheet, 0, len(byDate))
+	for _, timeSheet := range byDate {
+		timeSheets = append(timeSheets, timeSheet)
+	}
+
+	sort.Slice(timeSheets, func(i, j int) bool {
+		return timeSheets[i].TimesheetDate < timeSheets[j].TimesheetDate
+	})
+
+	return timeSheets, nil
+}
+
+func fetchChunk(client Client, chunk DateRange, opts FetchOptions) ([]TimeSheet, error) {
+	delay := opts.RetryDelay
+
+	for attempt := 0; ; attempt++ {
+		response, err := client.GetTimesheet(chunk.Start, chunk.End)
+		if err == nil {
+			return response.Result, nil
+		}
+
+		if attempt >= opts.Retries || !IsTransient(err) {
+			return nil, err
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// IsTransient reports whether the request which failed with err is worth retrying:
+// network errors, timeouts, 429 and 5xx responses.
+func IsTransient(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,113 @@
+package peopleapi
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeTimesheetClient struct {
+	Client
+
+	mu       sync.Mutex
+	calls    []DateRange
+	failures map[string]error
+}
+
+func (f *fakeTimesheetClient) GetTimesheet(startDate time.Time, endDate time.Time) (*GetTimesheetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, DateRange{Start: startDate, End: endDate})
+
+	key := startDate.Format("2006-01-02")
+	if err, ok := f.failures[key]; ok {
+		delete(f.failures, key)
+		return nil, err
+	}
+
+	// one timesheet at the start of every chunk, and a duplicate of the end date
+	return &GetTimesheetResponse{Result: []TimeSheet{
+		{TimesheetDate: startDate.Format("2006-01-02")},
+		{TimesheetDate: endDate.Format("2006-01-02")},
+		{TimesheetDate: endDate.Format("2006-01-02")},
+	}}, nil
+}
+
+func mustDate(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestSplitMonthly(t *testing.T) {
+	got := SplitMonthly(mustDate("2023-01-15"), mustDate("2023-03-10"))
+	want := []DateRange{
+		{Start: mustDate("2023-01-15"), End: mustDate("2023-01-31")},
+		{Start: mustDate("2023-02-01"), End: mustDate("2023-02-28")},
+		{Start: mustDate("2023-03-01"), End: mustDate("2023-03-10")},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitMonthly() got = %v, want %v", got, want)
+	}
+}
+
+func TestFetchTimesheetRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  map[string]error
+		wantErr   bool
+		wantCalls int
+	}{
+		{
+			name:      "merges and de-duplicates chunks",
+			wantCalls: 3,
+		},
+		{
+			name:      "retries transient errors",
+			failures:  map[string]error{"2023-02-01": &HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}},
+			wantCalls: 4,
+		},
+		{
+			name:      "fails on permanent errors",
+			failures:  map[string]error{"2023-02-01": errors.New("server response: invalid API key")},
+			wantErr:   true,
+			wantCalls: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeTimesheetClient{failures: tt.failures}
+			progress := 0
+
+			got, err := FetchTimesheetRange(client, mustDate("2023-01-15"), mustDate("2023-03-10"), FetchOptions{
+				Workers:  2,
+				Retries:  1,
+				Progress: func(done int, total int) { progress = done },
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchTimesheetRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(client.calls) != tt.wantCalls {
+				t.Errorf("FetchTimesheetRange() made %d calls, want %d", len(client.calls), tt.wantCalls)
+			}
+			if progress != 3 {
+				t.Errorf("FetchTimesheetRange() progress = %d, want 3", progress)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var dates []string
+			for _, timeSheet := range got {
+				dates = append(dates, timeSheet.TimesheetDate)
+			}
+			want := []string{"2023-01-15", "2023-01-31", "2023-02-01", "2023-02-28", "2023-03-01", "2023-03-10"}
+			if !reflect.DeepEqual(dates, want) {
+				t.Errorf("FetchTimesheetRange() got = %v, want %v", dates, want)
+			}
+		})
+	}
+}
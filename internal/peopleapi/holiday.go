@@ -0,0 +1,156 @@
+package peopleapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HolidayStatusApproved = "Approved"
+	HolidayStatusPending  = "Pending"
+
+	PartOfDayAM = "AM"
+	PartOfDayPM = "PM"
+)
+
+// HolidayRequest is a new holiday, Days is the number of working days it takes
+type HolidayRequest struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Days      float64
+	// PartOfTheDay is AM or PM for a half-day holiday, empty for whole days
+	PartOfTheDay string
+	Comments     string
+}
+
+func (c *client) GetHolidays(startDate time.Time, endDate time.Time) (*GetHolidayResponse, error) {
+	response := &GetHolidayResponse{}
+
+	err := c.post("/Holiday", map[string]string{
+		"APIKey":     c.auth.APIKey,
+		"EmployeeId": c.auth.EmployeeID,
+		"Action":     "GetHolidayDetail",
+		"StartDate":  startDate.Format("2006-01-02"),
+		"EndDate":    endDate.Format("2006-01-02"),
+	}, response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.IsError {
+		return nil, &ServerError{Message: response.Message}
+	}
+
+	return response, nil
+}
+
+func (c *client) RequestHoliday(request HolidayRequest) (*EditResponse, error) {
+	if request.EndDate.Before(request.StartDate) {
+		return nil, fmt.Errorf("holiday ends before it starts")
+	}
+
+	if request.PartOfTheDay != "" && !request.StartDate.Equal(request.EndDate) {
+		return nil, fmt.Errorf("a half-day holiday must start and end on the same day")
+	}
+
+	payload := map[string]string{
+		"APIKey":            c.auth.APIKey,
+		"EmployeeId":        c.auth.EmployeeID,
+		"Action":            ActionAddNewHoliday,
+		"StartDate":         request.StartDate.Format("2006-01-02"),
+		"EndDate":           request.EndDate.Format("2006-01-02"),
+		"DurationType":      "Days",
+		"DurationOfHoliday": strconv.FormatFloat(request.Days, 'f', -1, 64),
+		"PartOfTheDay":      request.PartOfTheDay,
+		"Comments":          request.Comments,
+	}
+
+	return c.edit("/Holiday", payload)
+}
+
+func (c *client) GetAbsences(startDate time.Time, endDate time.Time) (*GetAbsenceResponse, error) {
+	response := &GetAbsenceResponse{}
+
+	err := c.post("/Absence", map[string]string{
+		"APIKey":     c.auth.APIKey,
+		"EmployeeId": c.auth.EmployeeID,
+		"Action":     "GetAbsenceDetail",
+		"StartDate":  startDate.Format("2006-01-02"),
+		"EndDate":    endDate.Format("2006-01-02"),
+	}, response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.IsError {
+		return nil, &ServerError{Message: response.Message}
+	}
+
+	return response, nil
+}
+
+func (h Holiday) IsApproved() bool {
+	return strings.EqualFold(h.Status, HolidayStatusApproved)
+}
+
+func (h Holiday) IsPending() bool {
+	return strings.EqualFold(h.Status, HolidayStatusPending)
+}
+
+// Days returns the duration of the holiday in days, 0 if it is given in hours
+func (h Holiday) Days() float64 {
+	if strings.EqualFold(h.DurationType, "Hours") {
+		return 0
+	}
+
+	days, err := strconv.ParseFloat(strings.TrimSpace(h.Duration), 64)
+	if err != nil {
+		return 0
+	}
+	return days
+}
+
+// HolidayDates returns the dates taken as whole days by approved holidays,
+// half days are left out as they are still working days
+func HolidayDates(holidays []Holiday) map[string]bool {
+	dates := make(map[string]bool)
+
+	for _, holiday := range holidays {
+		if !holiday.IsApproved() || holiday.PartOfTheDay != "" {
+			continue
+		}
+
+		start, err := time.Parse("2006-01-02", holiday.StartDate)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse("2006-01-02", holiday.EndDate)
+		if err != nil {
+			continue
+		}
+
+		for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+			dates[date.Format("2006-01-02")] = true
+		}
+	}
+
+	return dates
+}
+
+// WorkingDays counts the days from start to end, both included, which fall on the workdays
+func WorkingDays(start time.Time, end time.Time, workdays []time.Weekday) int {
+	count := 0
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		for _, day := range workdays {
+			if date.Weekday() == day {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,48 @@
+package peopleapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHolidayDates(t *testing.T) {
+	holidays := []Holiday{
+		{StartDate: "2023-05-04", EndDate: "2023-05-05", Duration: "2", Status: "Approved"},
+		{StartDate: "2023-05-10", EndDate: "2023-05-10", Duration: "0.5", PartOfTheDay: PartOfDayAM, Status: "Approved"},
+		{StartDate: "2023-05-15", EndDate: "2023-05-15", Duration: "1", Status: "Pending"},
+		{StartDate: "2023-05-22", EndDate: "2023-05-22", Duration: "1", Status: "approved"},
+	}
+
+	want := map[string]bool{
+		"2023-05-04": true,
+		"2023-05-05": true,
+		"2023-05-22": true,
+	}
+
+	if got := HolidayDates(holidays); !reflect.DeepEqual(got, want) {
+		t.Errorf("HolidayDates() = %v, want %v", got, want)
+	}
+}
+
+func TestWorkingDays(t *testing.T) {
+	workdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	tests := []struct {
+		name  string
+		start string
+		end   string
+		want  int
+	}{
+		{name: "one day", start: "2023-05-02", end: "2023-05-02", want: 1},
+		{name: "over a weekend", start: "2023-05-04", end: "2023-05-09", want: 4},
+		{name: "weekend only", start: "2023-05-06", end: "2023-05-07", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WorkingDays(mustDate(tt.start), mustDate(tt.end), workdays); got != tt.want {
+				t.Errorf("WorkingDays() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
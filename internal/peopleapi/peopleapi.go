@@ -1,35 +1,64 @@
 package peopleapi
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 )
 
+const (
+	ActionCreateNewTimesheet  = "CreateNewTimesheet"
+	ActionUpdateTimesheet     = "UpdateTimesheet"
+	ActionAddProjectTimesheet = "AddProjectTimesheet"
+	ActionAddNewHoliday       = "AddNewHoliday"
+)
+
+const baseURL = "https://api.peoplehr.net"
+
+// ErrNotSupported is returned by backends which don't have the feature
+var ErrNotSupported = errors.New("not supported by this backend")
+
 type Client interface {
-	CreateNewTimesheet(time string) error
-	CheckInOut(slot string, time string) error
+	CreateNewTimesheet(time string) (*EditResponse, error)
+	CheckInOut(slot string, time string) (*EditResponse, error)
+	UpdateTimesheet(timeSheet TimeSheet) (*EditResponse, error)
 	GetTimesheet(startDate time.Time, endDate time.Time) (*GetTimesheetResponse, error)
+	AddProjectTimesheet(entry ProjectTimesheet) (*EditResponse, error)
+	GetProjectTimesheet(startDate time.Time, endDate time.Time) (*GetProjectTimesheetResponse, error)
+	GetHolidays(startDate time.Time, endDate time.Time) (*GetHolidayResponse, error)
+	RequestHoliday(request HolidayRequest) (*EditResponse, error)
+	GetAbsences(startDate time.Time, endDate time.Time) (*GetAbsenceResponse, error)
+	GetEmployee() (*Employee, error)
 }
 
-func NewClient(auth Auth) Client {
-	return &client{
+func NewClient(auth Auth, opts ...Option) Client {
+	c := &client{
 		auth: auth,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 type client struct {
 	auth Auth
+	// trace gets every request and response, nil to not trace
+	trace io.Writer
+	// dryRun gets the writes instead of PeopleHR, nil to send them
+	dryRun io.Writer
 }
 
-func (c *client) CreateNewTimesheet(time string) error {
+func (c *client) CreateNewTimesheet(time string) (*EditResponse, error) {
 	date := getTodayYYYYMMDD()
 	var now string
 
 	if time != "" {
 		if !IsValidTime(time) {
-			return fmt.Errorf("invalid time format")
+			return nil, fmt.Errorf("invalid time format")
 		}
 		now = time
 	} else {
@@ -39,33 +68,22 @@ func (c *client) CreateNewTimesheet(time string) error {
 	payload := map[string]string{
 		"APIKey":        c.auth.APIKey,
 		"EmployeeId":    c.auth.EmployeeID,
-		"Action":        "CreateNewTimesheet",
+		"Action":        ActionCreateNewTimesheet,
 		"TimesheetDate": date,
 		"TimeIn1":       now,
 	}
 
-	client := resty.New()
-	_, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetBody(payload).
-		SetResult(&EditResponse{}).
-		Post("https://api.peoplehr.net/Timesheet")
-
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return c.editTimesheet(payload)
 }
 
-func (c *client) CheckInOut(slot string, time string) error {
+func (c *client) CheckInOut(slot string, time string) (*EditResponse, error) {
 	date := getTodayYYYYMMDD()
 
 	var now string
 
 	if time != "" {
 		if !IsValidTime(time) {
-			return fmt.Errorf("invalid time format")
+			return nil, fmt.Errorf("invalid time format")
 		}
 		now = time
 	} else {
@@ -75,23 +93,83 @@ func (c *client) CheckInOut(slot string, time string) error {
 	payload := map[string]string{
 		"APIKey":        c.auth.APIKey,
 		"EmployeeId":    c.auth.EmployeeID,
-		"Action":        "UpdateTimesheet",
+		"Action":        ActionUpdateTimesheet,
 		"TimesheetDate": date,
 	}
 
 	payload[slot] = now
 
+	return c.editTimesheet(payload)
+}
+
+// UpdateTimesheet writes every slot of the timesheet at once, empty slots are cleared
+func (c *client) UpdateTimesheet(timeSheet TimeSheet) (*EditResponse, error) {
+	payload := map[string]string{
+		"APIKey":        c.auth.APIKey,
+		"EmployeeId":    c.auth.EmployeeID,
+		"Action":        ActionUpdateTimesheet,
+		"TimesheetDate": timeSheet.TimesheetDate,
+	}
+
+	for i, value := range timeSheet.slots() {
+		payload[SlotNames[i]] = ""
+		if *value == "" {
+			continue
+		}
+
+		clock, err := ParseClock(*value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", SlotNames[i], err)
+		}
+		payload[SlotNames[i]] = FormatClock(clock)[:5]
+	}
+
+	return c.editTimesheet(payload)
+}
+
+func (c *client) editTimesheet(payload map[string]string) (*EditResponse, error) {
+	return c.edit("/Timesheet", payload)
+}
+
+// edit posts a write to the endpoint, a response flagged as an error is returned with an error
+func (c *client) edit(endpoint string, payload map[string]string) (*EditResponse, error) {
+	if c.dryRun != nil {
+		fmt.Fprintf(c.dryRun, "Dry run, not sent: POST %s\n%s\n", baseURL+endpoint, c.redact(payload))
+		return &EditResponse{Message: "Dry run"}, nil
+	}
+
+	editResponse := &EditResponse{}
+
+	if err := c.post(endpoint, payload, editResponse); err != nil {
+		return editResponse, err
+	}
+
+	if editResponse.IsError {
+		return editResponse, &ServerError{Message: editResponse.Message}
+	}
+
+	return editResponse, nil
+}
+
+// post sends the payload to the endpoint and decodes the response into result
+func (c *client) post(endpoint string, payload map[string]string, result interface{}) error {
 	client := resty.New()
-	_, err := client.R().
+	c.setupTrace(client)
+
+	resp, err := client.R().
 		SetHeader("Content-Type", "application/json").
 		SetBody(payload).
-		SetResult(&EditResponse{}).
-		Post("https://api.peoplehr.net/Timesheet")
+		SetResult(result).
+		Post(baseURL + endpoint)
 
 	if err != nil {
 		return err
 	}
 
+	if resp.IsError() {
+		return &HTTPError{StatusCode: resp.StatusCode(), Status: resp.Status()}
+	}
+
 	return nil
 }
 
@@ -117,30 +195,44 @@ func (c *client) GetTimesheet(
 		endDateS = endDate.Format("2006-01-02")
 	}
 
-	client := resty.New()
-	_, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetBody(map[string]string{
-			"APIKey":     c.auth.APIKey,
-			"EmployeeId": c.auth.EmployeeID,
-			"Action":     "GetTimesheetDetail",
-			"EndDate":    endDateS,
-			"StartDate":  startDateS,
-		}).
-		SetResult(timeSheetResponse).
-		Post("https://api.peoplehr.net/Timesheet")
+	err := c.post("/Timesheet", map[string]string{
+		"APIKey":     c.auth.APIKey,
+		"EmployeeId": c.auth.EmployeeID,
+		"Action":     "GetTimesheetDetail",
+		"EndDate":    endDateS,
+		"StartDate":  startDateS,
+	}, timeSheetResponse)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if timeSheetResponse.IsError {
-		return nil, fmt.Errorf("server response: %s", timeSheetResponse.Message)
+		return nil, &ServerError{Message: timeSheetResponse.Message}
 	}
 
 	return timeSheetResponse, nil
 }
 
+// HTTPError is returned when PeopleHR responds with a non-2xx status
+type HTTPError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("server responded with %s", e.Status)
+}
+
+// ServerError is returned when PeopleHR flags the response as an error
+type ServerError struct {
+	Message string
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("server response: %s", e.Message)
+}
+
 func getTodayYYYYMMDD() string {
 	return time.Now().Format("2006-01-02")
 }
@@ -0,0 +1,91 @@
+package peopleapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AddProjectTimesheet adds a project time entry. The date defaults to today.
+func (c *client) AddProjectTimesheet(entry ProjectTimesheet) (*EditResponse, error) {
+	if entry.ProjectTimesheetDate == "" {
+		entry.ProjectTimesheetDate = getTodayYYYYMMDD()
+	}
+
+	if _, err := ParseHours(entry.TotalHours); err != nil {
+		return nil, err
+	}
+
+	payload := map[string]string{
+		"APIKey":               c.auth.APIKey,
+		"EmployeeId":           c.auth.EmployeeID,
+		"Action":               ActionAddProjectTimesheet,
+		"ProjectTimesheetDate": entry.ProjectTimesheetDate,
+		"Project":              entry.Project,
+		"Task":                 entry.Task,
+		"TotalHours":           entry.TotalHours,
+		"StartTime":            entry.StartTime,
+		"EndTime":              entry.EndTime,
+		"Notes":                entry.Notes,
+	}
+
+	return c.edit("/ProjectTimesheet", payload)
+}
+
+func (c *client) GetProjectTimesheet(startDate time.Time, endDate time.Time) (*GetProjectTimesheetResponse, error) {
+	response := &GetProjectTimesheetResponse{}
+
+	err := c.post("/ProjectTimesheet", map[string]string{
+		"APIKey":     c.auth.APIKey,
+		"EmployeeId": c.auth.EmployeeID,
+		"Action":     "GetProjectTimesheetDetail",
+		"StartDate":  startDate.Format("2006-01-02"),
+		"EndDate":    endDate.Format("2006-01-02"),
+	}, response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.IsError {
+		return nil, &ServerError{Message: response.Message}
+	}
+
+	return response, nil
+}
+
+// Duration returns TotalHours as a duration
+func (p ProjectTimesheet) Duration() (time.Duration, error) {
+	return ParseHours(p.TotalHours)
+}
+
+// ParseHours parses the hours of a project entry, HH:MM or decimal hours like 1.5
+func ParseHours(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	if hours, minutes, ok := strings.Cut(s, ":"); ok {
+		h, err := strconv.Atoi(hours)
+		if err != nil {
+			return 0, fmt.Errorf("invalid hours %q", s)
+		}
+		m, err := strconv.Atoi(minutes)
+		if err != nil || m < 0 || m > 59 || h < 0 {
+			return 0, fmt.Errorf("invalid hours %q", s)
+		}
+		return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+	}
+
+	hours, err := strconv.ParseFloat(s, 64)
+	if err != nil || hours < 0 {
+		return 0, fmt.Errorf("invalid hours %q", s)
+	}
+
+	return time.Duration(hours * float64(time.Hour)).Round(time.Minute), nil
+}
+
+// FormatHours formats a duration as the HH:MM PeopleHR expects
+func FormatHours(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,56 @@
+package peopleapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseHours(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "01:30", want: 90 * time.Minute},
+		{in: "7:05", want: 7*time.Hour + 5*time.Minute},
+		{in: "1.5", want: 90 * time.Minute},
+		{in: "2", want: 2 * time.Hour},
+		{in: "1:75", wantErr: true},
+		{in: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseHours(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHours() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetProjectTimesheetResponseUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{name: "entries", data: `{"isError":false,"Result":[{"TimesheetProjectId":12,"Project":"Acme","Task":"Dev","TotalHours":"01:30"}]}`, want: 1},
+		{name: "empty string", data: `{"isError":false,"Result":""}`, want: 0},
+		{name: "empty object", data: `{"isError":false,"Result":{}}`, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response GetProjectTimesheetResponse
+			if err := json.Unmarshal([]byte(tt.data), &response); err != nil {
+				t.Fatal(err)
+			}
+			if len(response.Result) != tt.want {
+				t.Errorf("Result = %v, want %d entries", response.Result, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package peopleapi
+
+import (
+	"sync"
+	"time"
+)
+
+// MemberTimesheets are the timesheets of one team member, or the error fetching them
+type MemberTimesheets struct {
+	EmployeeID string
+	TimeSheets []TimeSheet
+	Err        error
+}
+
+// FetchTeamTimesheets fetches the timesheets of every employee concurrently,
+// opts.Workers employees at a time. clientFor returns the client of an employee,
+// usually with an admin API key. The result keeps the order of employeeIDs,
+// a failing employee doesn't fail the others.
+func FetchTeamTimesheets(clientFor func(employeeID string) Client, employeeIDs []string, startDate time.Time, endDate time.Time, opts FetchOptions) []MemberTimesheets {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultFetchWorkers
+	}
+
+	// the months of one employee are fetched one by one, the employees in parallel
+	memberOpts := FetchOptions{
+		Workers:    1,
+		Retries:    opts.Retries,
+		RetryDelay: opts.RetryDelay,
+	}
+
+	results := make([]MemberTimesheets, len(employeeIDs))
+	jobs := make(chan int)
+
+	var mu sync.Mutex
+	done := 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				employeeID := employeeIDs[index]
+				timeSheets, err := FetchTimesheetRange(clientFor(employeeID), startDate, endDate, memberOpts)
+				results[index] = MemberTimesheets{EmployeeID: employeeID, TimeSheets: timeSheets, Err: err}
+
+				if opts.Progress != nil {
+					mu.Lock()
+					done++
+					opts.Progress(done, len(employeeIDs))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for index := range employeeIDs {
+		jobs <- index
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	return results
+}
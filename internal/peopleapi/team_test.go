@@ -0,0 +1,63 @@
+package peopleapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeEmployeeClient struct {
+	Client
+
+	employeeID string
+	err        error
+}
+
+func (f *fakeEmployeeClient) GetTimesheet(startDate time.Time, endDate time.Time) (*GetTimesheetResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &GetTimesheetResponse{Result: []TimeSheet{
+		{TimesheetDate: startDate.Format("2006-01-02"), TimeIn1: f.employeeID},
+	}}, nil
+}
+
+func TestFetchTeamTimesheets(t *testing.T) {
+	failure := errors.New("invalid employee")
+
+	clientFor := func(employeeID string) Client {
+		if employeeID == "broken" {
+			return &fakeEmployeeClient{employeeID: employeeID, err: failure}
+		}
+		return &fakeEmployeeClient{employeeID: employeeID}
+	}
+
+	employeeIDs := []string{"a", "broken", "c", "d", "e"}
+
+	results := FetchTeamTimesheets(clientFor, employeeIDs, mustDate("2023-01-15"), mustDate("2023-02-10"), FetchOptions{Workers: 2})
+
+	if len(results) != len(employeeIDs) {
+		t.Fatalf("FetchTeamTimesheets() returned %d results, want %d", len(results), len(employeeIDs))
+	}
+
+	for i, result := range results {
+		if result.EmployeeID != employeeIDs[i] {
+			t.Errorf("result %d is of %s, want %s", i, result.EmployeeID, employeeIDs[i])
+		}
+
+		if result.EmployeeID == "broken" {
+			if !errors.Is(result.Err, failure) {
+				t.Errorf("result of %s error = %v, want %v", result.EmployeeID, result.Err, failure)
+			}
+			continue
+		}
+
+		if result.Err != nil {
+			t.Errorf("result of %s error = %v", result.EmployeeID, result.Err)
+		}
+		// one timesheet per monthly chunk, each from the client of the employee
+		if len(result.TimeSheets) != 2 || result.TimeSheets[0].TimeIn1 != result.EmployeeID {
+			t.Errorf("result of %s timesheets = %v", result.EmployeeID, result.TimeSheets)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package peopleapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Redacted replaces secrets in traces and dry runs
+const Redacted = "[REDACTED]"
+
+// secretKeys are the (lowercase) payload and response fields never shown
+var secretKeys = []string{"apikey", "password", "token", "secret"}
+
+// Option configures the PeopleHR client
+type Option func(*client)
+
+// WithTrace logs every request and response to w, with the secrets redacted
+func WithTrace(w io.Writer) Option {
+	return func(c *client) {
+		c.trace = w
+	}
+}
+
+// WithDryRun prints the writes to w instead of sending them, reads are still sent
+func WithDryRun(w io.Writer) Option {
+	return func(c *client) {
+		c.dryRun = w
+	}
+}
+
+// setupTrace hooks the trace log into the resty client
+func (c *client) setupTrace(r *resty.Client) {
+	if c.trace == nil {
+		return
+	}
+
+	r.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		fmt.Fprintf(c.trace, "--> %s %s\n%s\n", req.Method, req.URL, c.redact(req.Body))
+		return nil
+	})
+	r.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		fmt.Fprintf(c.trace, "<-- %s in %s\n%s\n", resp.Status(), resp.Time().Round(time.Millisecond), c.redact(resp.Body()))
+		return nil
+	})
+	r.OnError(func(req *resty.Request, err error) {
+		fmt.Fprintf(c.trace, "<-- %s %s failed: %s\n", req.Method, req.URL, c.redactString(err.Error()))
+	})
+}
+
+// redact renders a payload or a response body as indented JSON with the secrets replaced,
+// a body which isn't JSON is shown as it is, minus the API key
+func (c *client) redact(body interface{}) string {
+	var value interface{}
+
+	switch b := body.(type) {
+	case []byte:
+		if err := json.Unmarshal(b, &value); err != nil {
+			return c.redactString(string(b))
+		}
+	case map[string]string:
+		value = redactPayload(b)
+	default:
+		value = b
+	}
+
+	data, err := json.MarshalIndent(redactValue(value), "", "  ")
+	if err != nil {
+		return c.redactString(fmt.Sprint(value))
+	}
+	return c.redactString(string(data))
+}
+
+// redactString blanks the API key wherever it turns up
+func (c *client) redactString(s string) string {
+	if c.auth.APIKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, c.auth.APIKey, Redacted)
+}
+
+// redactPayload copies the payload into a generic map, redactValue replaces its secret fields
+func redactPayload(payload map[string]string) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(payload))
+	for key, value := range payload {
+		redacted[key] = value
+	}
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSecretKey(key) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package peopleapi
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	var out bytes.Buffer
+	c := NewClient(Auth{APIKey: "secret-key", EmployeeID: "PW1"}, WithDryRun(&out))
+
+	resp, err := c.CheckInOut("TimeOut3", "17:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.IsError {
+		t.Errorf("CheckInOut() = %+v, want a successful dry run", resp)
+	}
+
+	got := out.String()
+	for _, want := range []string{"Dry run", "/Timesheet", `"Action": "UpdateTimesheet"`, `"TimeOut3": "17:00"`, `"APIKey": "[REDACTED]"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("dry run output %q doesn't contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "secret-key") {
+		t.Errorf("dry run output %q shows the API key", got)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	c := &client{auth: Auth{APIKey: "secret-key"}}
+
+	tests := []struct {
+		name    string
+		body    interface{}
+		want    []string
+		notWant string
+	}{
+		{
+			name:    "payload",
+			body:    map[string]string{"APIKey": "secret-key", "Action": "GetTimesheetDetail"},
+			want:    []string{`"APIKey": "[REDACTED]"`, `"Action": "GetTimesheetDetail"`},
+			notWant: "secret-key",
+		},
+		{
+			name:    "nested response",
+			body:    []byte(`{"isError":false,"Result":[{"Token":"t0k3n","TimeIn1":"09:00:00"}]}`),
+			want:    []string{`"Token": "[REDACTED]"`, `"TimeIn1": "09:00:00"`},
+			notWant: "t0k3n",
+		},
+		{
+			name:    "not JSON",
+			body:    []byte("bad key secret-key"),
+			want:    []string{"bad key [REDACTED]"},
+			notWant: "secret-key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.redact(tt.body)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("redact() = %q, want it to contain %q", got, want)
+				}
+			}
+			if strings.Contains(got, tt.notWant) {
+				t.Errorf("redact() = %q shows %q", got, tt.notWant)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package peopleapi
 
-import "reflect"
+import (
+	"fmt"
+)
 
 // ActionType is the type of action: In or Out
 type ActionType string
@@ -10,74 +12,89 @@ const (
 	ActionTypeOut ActionType = "Out"
 )
 
+// SlotCount is the number of In/Out pairs of a timesheet
+const SlotCount = 15
+
+// SlotNames lists the timesheet slots in order: TimeIn1, TimeOut1, ... TimeOut15
+var SlotNames = func() []string {
+	names := make([]string, 0, SlotCount*2)
+	for i := 1; i <= SlotCount; i++ {
+		names = append(names, fmt.Sprintf("TimeIn%d", i), fmt.Sprintf("TimeOut%d", i))
+	}
+	return names
+}()
+
+// SlotIndex returns the position of the slot in SlotNames, -1 if it is unknown
+func SlotIndex(slot string) int {
+	for i, name := range SlotNames {
+		if name == slot {
+			return i
+		}
+	}
+	return -1
+}
+
 type Action struct {
+	Slot string
 	Type ActionType
 	Time string
 }
 
-func TimeSheetToActionsList(timeSheet *TimeSheet) []Action {
-	var actions []Action
-
-	fields := reflect.ValueOf(*timeSheet)
-
-	for i := 0; i < fields.NumField(); i++ {
-		field := fields.Field(i)
-
-		if field.String() == "" {
-			continue
-		}
-
-		if field.String() != "" {
-			fieldName := fields.Type().Field(i).Name
-
-			actionType := ActionTypeIn
-
-			if fieldName[0:6] == "TimeIn" {
-				actionType = ActionTypeIn
-			} else if fieldName[0:7] == "TimeOut" {
-				actionType = ActionTypeOut
-			} else {
-				continue
-			}
-
-			actions = append(actions, Action{Type: actionType, Time: field.String()})
-		}
+// slots returns the TimeInN/TimeOutN fields in the order of SlotNames
+func (t *TimeSheet) slots() [SlotCount * 2]*string {
+	return [SlotCount * 2]*string{
+		&t.TimeIn1, &t.TimeOut1,
+		&t.TimeIn2, &t.TimeOut2,
+		&t.TimeIn3, &t.TimeOut3,
+		&t.TimeIn4, &t.TimeOut4,
+		&t.TimeIn5, &t.TimeOut5,
+		&t.TimeIn6, &t.TimeOut6,
+		&t.TimeIn7, &t.TimeOut7,
+		&t.TimeIn8, &t.TimeOut8,
+		&t.TimeIn9, &t.TimeOut9,
+		&t.TimeIn10, &t.TimeOut10,
+		&t.TimeIn11, &t.TimeOut11,
+		&t.TimeIn12, &t.TimeOut12,
+		&t.TimeIn13, &t.TimeOut13,
+		&t.TimeIn14, &t.TimeOut14,
+		&t.TimeIn15, &t.TimeOut15,
 	}
-
-	return actions
 }
 
-func CanCheckIn(actions []Action) bool {
-	if len(actions) == 0 {
-		return true
+// Slot returns the value of the named slot, "" if the slot is unknown
+func (t *TimeSheet) Slot(name string) string {
+	if i := SlotIndex(name); i >= 0 {
+		return *t.slots()[i]
 	}
-	lastAction := actions[len(actions)-1]
-	return lastAction.Type == ActionTypeOut
+	return ""
 }
 
-func CanCheckOut(actions []Action) bool {
-	if len(actions) == 0 {
-		return false
+// SetSlot sets the value of the named slot
+func (t *TimeSheet) SetSlot(name string, value string) error {
+	i := SlotIndex(name)
+	if i < 0 {
+		return fmt.Errorf("unknown slot %q", name)
 	}
-	lastAction := actions[len(actions)-1]
-	return lastAction.Type == ActionTypeIn
+	*t.slots()[i] = value
+	return nil
 }
 
-func GetNextSlotName(timeSheet TimeSheet) string {
-	//find the first field that is empty and start with TimeIn or TimeOut
-	fields := reflect.ValueOf(timeSheet)
-
-	for i := 0; i < fields.NumField(); i++ {
-		field := fields.Field(i)
+// TimeSheetToActionsList returns the filled slots in order
+func TimeSheetToActionsList(timeSheet *TimeSheet) []Action {
+	var actions []Action
 
-		if field.String() == "" {
-			fieldName := fields.Type().Field(i).Name
+	for i, value := range timeSheet.slots() {
+		if *value == "" {
+			continue
+		}
 
-			if fieldName[0:6] == "TimeIn" || fieldName[0:7] == "TimeOut" {
-				return fieldName
-			}
+		actionType := ActionTypeIn
+		if i%2 == 1 {
+			actionType = ActionTypeOut
 		}
+
+		actions = append(actions, Action{Slot: SlotNames[i], Type: actionType, Time: *value})
 	}
 
-	return ""
+	return actions
 }
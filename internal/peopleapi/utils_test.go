@@ -26,18 +26,22 @@ func TestTimeSheetToActionsList(t *testing.T) {
 			},
 			want: []Action{
 				{
+					Slot: "TimeIn1",
 					Type: ActionTypeIn,
 					Time: "09:00",
 				},
 				{
+					Slot: "TimeOut1",
 					Type: ActionTypeOut,
 					Time: "10:00",
 				},
 				{
+					Slot: "TimeIn2",
 					Type: ActionTypeIn,
 					Time: "11:00",
 				},
 				{
+					Slot: "TimeOut2",
 					Type: ActionTypeOut,
 					Time: "12:00",
 				},
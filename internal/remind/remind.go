@@ -0,0 +1,110 @@
+package remind
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+	"github.com/harnyk/wink/internal/shell"
+)
+
+const (
+	KindCheckout = "checkout"
+	KindCheckin  = "checkin"
+	KindMaxDaily = "max_daily"
+)
+
+// Rules are the checks made on today's timesheet, zero values disable a check
+type Rules struct {
+	// CheckoutAfter is the time since midnight after which one should be checked out
+	CheckoutAfter time.Duration
+	// CheckinBy is the time since midnight by which one should be checked in on workdays
+	CheckinBy time.Duration
+	MaxDaily  time.Duration
+	Workdays  []time.Weekday
+}
+
+type Reminder struct {
+	Kind    string
+	Message string
+}
+
+// Check returns the reminders due at now. timeSheet is today's timesheet,
+// nil if there is none yet.
+func Check(timeSheet *peopleapi.TimeSheet, now time.Time, rules Rules) ([]Reminder, error) {
+	var reminders []Reminder
+
+	clock := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+
+	var total *report.TimesheetDailyTotal
+	if timeSheet != nil {
+		var err error
+		total, err = report.CalculateHours(timeSheet)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	checkedIn := false
+	if total != nil {
+		_, checkedIn = total.CheckedInSince()
+	}
+
+	if rules.CheckoutAfter > 0 && checkedIn && clock >= rules.CheckoutAfter {
+		reminders = append(reminders, Reminder{
+			Kind:    KindCheckout,
+			Message: fmt.Sprintf("You are still checked in, it's %s", now.Format("15:04")),
+		})
+	}
+
+	if rules.CheckinBy > 0 && isWorkday(now, rules.Workdays) && clock >= rules.CheckinBy &&
+		(total == nil || len(total.Actions) == 0) {
+		reminders = append(reminders, Reminder{
+			Kind:    KindCheckin,
+			Message: fmt.Sprintf("You have not checked in today, it's %s", now.Format("15:04")),
+		})
+	}
+
+	if rules.MaxDaily > 0 && total != nil {
+		worked := total.WorkedUpTo(now)
+		if worked > rules.MaxDaily {
+			reminders = append(reminders, Reminder{
+				Kind:    KindMaxDaily,
+				Message: fmt.Sprintf("You have worked %s today, more than %s", report.FormatDuration(worked), report.FormatDuration(rules.MaxDaily)),
+			})
+		}
+	}
+
+	return reminders, nil
+}
+
+func isWorkday(t time.Time, workdays []time.Weekday) bool {
+	for _, day := range workdays {
+		if t.Weekday() == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify sends the reminder through the command, with the text in $WINK_MESSAGE
+// and the kind in $WINK_REMINDER. Without a command it rings the terminal bell.
+func Notify(command string, reminder Reminder) error {
+	if command == "" {
+		fmt.Printf("\a%s\n", reminder.Message)
+		return nil
+	}
+
+	cmd := shell.Command(command)
+	cmd.Env = append(os.Environ(), "WINK_MESSAGE="+reminder.Message, "WINK_REMINDER="+reminder.Kind)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("notify command failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package remind_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/remind"
+)
+
+func TestCheck(t *testing.T) {
+	rules := remind.Rules{
+		CheckoutAfter: 18 * time.Hour,
+		CheckinBy:     10 * time.Hour,
+		MaxDaily:      10 * time.Hour,
+		Workdays:      []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	}
+
+	// 2023-05-02 is a Tuesday, 2023-05-06 a Saturday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2023, 5, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	checkedIn := &peopleapi.TimeSheet{TimesheetDate: "2023-05-02", TimeIn1: "09:00:00"}
+	checkedOut := &peopleapi.TimeSheet{TimesheetDate: "2023-05-02", TimeIn1: "09:00:00", TimeOut1: "17:00:00"}
+
+	tests := []struct {
+		name      string
+		timeSheet *peopleapi.TimeSheet
+		now       time.Time
+		want      []string
+	}{
+		{name: "checked in during the day", timeSheet: checkedIn, now: at(2, 12, 0)},
+		{name: "still checked in", timeSheet: checkedIn, now: at(2, 18, 30), want: []string{remind.KindCheckout}},
+		{name: "checked out in the evening", timeSheet: checkedOut, now: at(2, 18, 30)},
+		{name: "no check-in yet", now: at(2, 9, 0)},
+		{name: "no check-in by 10", now: at(2, 10, 15), want: []string{remind.KindCheckin}},
+		{name: "no check-in on a weekend", now: at(6, 10, 15)},
+		{name: "max daily crossed", timeSheet: checkedIn, now: at(2, 19, 30), want: []string{remind.KindCheckout, remind.KindMaxDaily}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminders, err := remind.Check(tt.timeSheet, tt.now, rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, reminder := range reminders {
+				got = append(got, reminder.Kind)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
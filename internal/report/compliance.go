@@ -0,0 +1,162 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BreakRule requires a minimum total break once the time worked
+// during a day exceeds After.
+type BreakRule struct {
+	After    time.Duration
+	MinBreak time.Duration
+}
+
+// ComplianceRules are the working-time rules a report is checked against.
+// Zero thresholds disable the corresponding check.
+type ComplianceRules struct {
+	Name string
+	// Breaks are the minimum break rules, the strictest applicable one wins
+	Breaks []BreakRule
+	// MinBreakLength is the shortest break that counts towards the break rules
+	MinBreakLength time.Duration
+	// MaxDaily is the maximum time worked per day
+	MaxDaily time.Duration
+	// MinRest is the minimum rest between the last check-out of one day
+	// and the first check-in of the next
+	MinRest time.Duration
+}
+
+const (
+	RuleMinBreak = "min_break"
+	RuleMaxDaily = "max_daily"
+	RuleMinRest  = "min_rest"
+)
+
+// Violation is a single broken working-time rule.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// CompliancePresets are the built-in working-time rule sets.
+var CompliancePresets = map[string]ComplianceRules{
+	// German Arbeitszeitgesetz
+	"de": {
+		Name: "de",
+		Breaks: []BreakRule{
+			{After: 6 * time.Hour, MinBreak: 30 * time.Minute},
+			{After: 9 * time.Hour, MinBreak: 45 * time.Minute},
+		},
+		MinBreakLength: 15 * time.Minute,
+		MaxDaily:       10 * time.Hour,
+		MinRest:        11 * time.Hour,
+	},
+	// EU Working Time Directive defaults
+	"eu": {
+		Name: "eu",
+		Breaks: []BreakRule{
+			{After: 6 * time.Hour, MinBreak: 20 * time.Minute},
+		},
+		MaxDaily: 13 * time.Hour,
+		MinRest:  11 * time.Hour,
+	},
+	// UK Working Time Regulations
+	"uk": {
+		Name: "uk",
+		Breaks: []BreakRule{
+			{After: 6 * time.Hour, MinBreak: 20 * time.Minute},
+		},
+		MinRest: 11 * time.Hour,
+	},
+}
+
+// GetCompliancePreset returns the built-in rule set with the given name.
+func GetCompliancePreset(name string) (ComplianceRules, error) {
+	rules, ok := CompliancePresets[name]
+	if !ok {
+		names := make([]string, 0, len(CompliancePresets))
+		for n := range CompliancePresets {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return ComplianceRules{}, fmt.Errorf("unknown compliance preset %q, available: %s", name, strings.Join(names, ", "))
+	}
+	return rules, nil
+}
+
+// CheckCompliance evaluates the rules against the daily totals and
+// returns the violations keyed by date (2006-01-02).
+func CheckCompliance(totals []TimesheetDailyTotal, rules ComplianceRules) map[string][]Violation {
+	violations := make(map[string][]Violation)
+
+	sorted := make([]TimesheetDailyTotal, len(totals))
+	copy(sorted, totals)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	for i, total := range sorted {
+		key := total.Date.Format("2006-01-02")
+
+		if v, ok := checkBreaks(&total, rules); !ok {
+			violations[key] = append(violations[key], v)
+		}
+
+		if rules.MaxDaily > 0 && total.Duration > rules.MaxDaily {
+			violations[key] = append(violations[key], Violation{
+				Rule: RuleMaxDaily,
+				Message: fmt.Sprintf("worked %s, maximum is %s",
+					FormatDuration(total.Duration), FormatDuration(rules.MaxDaily)),
+			})
+		}
+
+		if rules.MinRest > 0 && i > 0 {
+			prev := sorted[i-1]
+			if !prev.LastOut.IsZero() && !total.FirstIn.IsZero() {
+				rest := total.FirstIn.Sub(prev.LastOut)
+				if rest < rules.MinRest {
+					violations[key] = append(violations[key], Violation{
+						Rule: RuleMinRest,
+						Message: fmt.Sprintf("rested %s since %s, minimum is %s",
+							FormatDuration(rest), prev.LastOut.Format("02-Jan 15:04"), FormatDuration(rules.MinRest)),
+					})
+				}
+			}
+		}
+	}
+
+	return violations
+}
+
+func checkBreaks(total *TimesheetDailyTotal, rules ComplianceRules) (Violation, bool) {
+	var required BreakRule
+	for _, rule := range rules.Breaks {
+		if total.Duration > rule.After && rule.MinBreak > required.MinBreak {
+			required = rule
+		}
+	}
+
+	if required.MinBreak == 0 {
+		return Violation{}, true
+	}
+
+	var taken time.Duration
+	for _, brk := range total.Breaks() {
+		if brk.Duration() >= rules.MinBreakLength {
+			taken += brk.Duration()
+		}
+	}
+
+	if taken >= required.MinBreak {
+		return Violation{}, true
+	}
+
+	return Violation{
+		Rule: RuleMinBreak,
+		Message: fmt.Sprintf("break of %s after %s worked, minimum is %s",
+			FormatDuration(taken), FormatDuration(total.Duration), FormatDuration(required.MinBreak)),
+	}, false
+}
@@ -0,0 +1,83 @@
+package report_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+)
+
+func TestCheckCompliance(t *testing.T) {
+	rules, err := report.GetCompliancePreset("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		timeSheets []peopleapi.TimeSheet
+		want       map[string][]string
+	}{
+		{
+			name: "compliant days",
+			timeSheets: []peopleapi.TimeSheet{
+				{TimesheetDate: "2023-03-01", TimeIn1: "08:00:00", TimeOut1: "12:00:00", TimeIn2: "12:30:00", TimeOut2: "17:00:00"},
+				{TimesheetDate: "2023-03-02", TimeIn1: "08:00:00", TimeOut1: "13:00:00"},
+			},
+			want: map[string][]string{},
+		},
+		{
+			name: "short break after 6 hours",
+			timeSheets: []peopleapi.TimeSheet{
+				{TimesheetDate: "2023-03-01", TimeIn1: "08:00:00", TimeOut1: "12:00:00", TimeIn2: "12:20:00", TimeOut2: "15:00:00"},
+			},
+			want: map[string][]string{"2023-03-01": {report.RuleMinBreak}},
+		},
+		{
+			name: "breaks shorter than the minimum length do not count",
+			timeSheets: []peopleapi.TimeSheet{
+				{TimesheetDate: "2023-03-01", TimeIn1: "08:00:00", TimeOut1: "10:00:00", TimeIn2: "10:10:00", TimeOut2: "12:00:00", TimeIn3: "12:10:00", TimeOut3: "12:20:00", TimeIn4: "12:30:00", TimeOut4: "15:00:00"},
+			},
+			want: map[string][]string{"2023-03-01": {report.RuleMinBreak}},
+		},
+		{
+			name: "long day needs 45 minutes and exceeds the maximum",
+			timeSheets: []peopleapi.TimeSheet{
+				{TimesheetDate: "2023-03-01", TimeIn1: "07:00:00", TimeOut1: "12:00:00", TimeIn2: "12:30:00", TimeOut2: "18:30:00"},
+			},
+			want: map[string][]string{"2023-03-01": {report.RuleMinBreak, report.RuleMaxDaily}},
+		},
+		{
+			name: "short rest period",
+			timeSheets: []peopleapi.TimeSheet{
+				{TimesheetDate: "2023-03-02", TimeIn1: "06:00:00", TimeOut1: "10:00:00"},
+				{TimesheetDate: "2023-03-01", TimeIn1: "18:00:00", TimeOut1: "22:00:00"},
+			},
+			want: map[string][]string{"2023-03-02": {report.RuleMinRest}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var totals []report.TimesheetDailyTotal
+			for _, timeSheet := range tt.timeSheets {
+				total, err := report.CalculateHours(&timeSheet)
+				if err != nil {
+					t.Fatal(err)
+				}
+				totals = append(totals, *total)
+			}
+
+			got := map[string][]string{}
+			for date, violations := range report.CheckCompliance(totals, rules) {
+				for _, violation := range violations {
+					got[date] = append(got[date], violation.Rule)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckCompliance() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
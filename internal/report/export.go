@@ -0,0 +1,57 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+// RenderExportJSONLines renders one timesheet per line in the PeopleHR wire format,
+// the format of the local backend file
+func RenderExportJSONLines(timeSheets []peopleapi.TimeSheet) ([]byte, error) {
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	for _, timeSheet := range timeSheets {
+		if err := encoder.Encode(timeSheet); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RenderExportCSV renders one row per work interval, an interval still open has no end
+func RenderExportCSV(timeSheets []peopleapi.TimeSheet) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{{"date", "start", "end", "minutes"}}
+
+	for _, total := range calculateTotals(timeSheets) {
+		date := total.DateString()
+
+		for _, interval := range total.Intervals {
+			rows = append(rows, []string{
+				date,
+				interval.Start.Format("15:04:05"),
+				interval.End.Format("15:04:05"),
+				strconv.Itoa(int(interval.Duration().Round(time.Minute).Minutes())),
+			})
+		}
+
+		if since, ok := total.CheckedInSince(); ok {
+			rows = append(rows, []string{date, since.Format("15:04:05"), "", ""})
+		}
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,29 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+)
+
+func TestRenderExportCSV(t *testing.T) {
+	timeSheets := []peopleapi.TimeSheet{
+		{TimesheetDate: "2023-05-03", TimeIn1: "09:00:00"},
+		{TimesheetDate: "2023-05-02", TimeIn1: "08:00:00", TimeOut1: "12:00:00", TimeIn2: "12:30:00", TimeOut2: "16:45:00"},
+	}
+
+	got, err := report.RenderExportCSV(timeSheets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "date,start,end,minutes\n" +
+		"2023-05-02,08:00:00,12:00:00,240\n" +
+		"2023-05-02,12:30:00,16:45:00,255\n" +
+		"2023-05-03,09:00:00,,\n"
+
+	if string(got) != want {
+		t.Errorf("RenderExportCSV() = %q, want %q", got, want)
+	}
+}
@@ -1,19 +1,61 @@
 package report
 
-import "math"
+import (
+	"math"
+	"time"
+)
 
 type TimesheetDailyTotalJSON struct {
-	Date              string  `json:"date"`
-	Hours             float64 `json:"hours"`
-	IsComplete        bool    `json:"is_complete"`
-	IsInvalidSequence bool    `json:"is_invalid_sequence"`
+	Date              string         `json:"date"`
+	Hours             float64        `json:"hours"`
+	IsComplete        bool           `json:"is_complete"`
+	IsInvalidSequence bool           `json:"is_invalid_sequence"`
+	FirstIn           string         `json:"first_in,omitempty"`
+	LastOut           string         `json:"last_out,omitempty"`
+	Intervals         []IntervalJSON `json:"intervals"`
+	Breaks            []IntervalJSON `json:"breaks"`
+	Violations        []Violation    `json:"violations,omitempty"`
+	IsHoliday         bool           `json:"is_holiday,omitempty"`
+	Error             string         `json:"error,omitempty"`
+}
+
+type IntervalJSON struct {
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Minutes int    `json:"minutes"`
 }
 
 func NewTimesheetDailyTotalJSON(t *TimesheetDailyTotal) TimesheetDailyTotalJSON {
 	return TimesheetDailyTotalJSON{
-		Date:              t.Date.Format("2006-01-02"),
+		Date:              t.DateString(),
 		Hours:             math.Round(t.Duration.Hours()*10) / 10,
 		IsComplete:        t.IsComplete,
 		IsInvalidSequence: t.IsInvalidSequence,
+		FirstIn:           formatClockJSON(t.FirstIn),
+		LastOut:           formatClockJSON(t.LastOut),
+		Intervals:         newIntervalsJSON(t.Intervals),
+		Breaks:            newIntervalsJSON(t.Breaks()),
+		Error:             t.Error,
+	}
+}
+
+func newIntervalsJSON(intervals []Interval) []IntervalJSON {
+	result := []IntervalJSON{}
+
+	for _, interval := range intervals {
+		result = append(result, IntervalJSON{
+			Start:   interval.Start.Format("15:04"),
+			End:     interval.End.Format("15:04"),
+			Minutes: int(interval.Duration().Round(time.Minute).Minutes()),
+		})
+	}
+
+	return result
+}
+
+func formatClockJSON(t time.Time) string {
+	if t.IsZero() {
+		return ""
 	}
+	return t.Format("15:04")
 }
@@ -0,0 +1,150 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+// ProjectTotal is the time logged on a project within the report range
+type ProjectTotal struct {
+	Project  string
+	Duration time.Duration
+	Tasks    []TaskTotal
+	// Errors describe the entries whose hours couldn't be read, they count no time
+	Errors []string
+}
+
+type TaskTotal struct {
+	Task     string
+	Duration time.Duration
+}
+
+type ProjectTotalJSON struct {
+	Project string          `json:"project"`
+	Hours   float64         `json:"hours"`
+	Tasks   []TaskTotalJSON `json:"tasks"`
+	Errors  []string        `json:"errors,omitempty"`
+}
+
+type TaskTotalJSON struct {
+	Task  string  `json:"task"`
+	Hours float64 `json:"hours"`
+}
+
+// CalculateProjectTotals sums the project entries by project and task,
+// entries with unparsable hours are listed in the Errors of their project
+func CalculateProjectTotals(entries []peopleapi.ProjectTimesheet) []ProjectTotal {
+	byProject := make(map[string]map[string]time.Duration)
+	errorsByProject := make(map[string][]string)
+
+	for _, entry := range entries {
+		if byProject[entry.Project] == nil {
+			byProject[entry.Project] = make(map[string]time.Duration)
+		}
+
+		duration, err := entry.Duration()
+		if err != nil {
+			errorsByProject[entry.Project] = append(errorsByProject[entry.Project],
+				fmt.Sprintf("%s %s: %s", entry.ProjectTimesheetDate, entry.Task, err))
+			continue
+		}
+
+		byProject[entry.Project][entry.Task] += duration
+	}
+
+	totals := []ProjectTotal{}
+	for project, tasks := range byProject {
+		total := ProjectTotal{Project: project, Errors: errorsByProject[project]}
+		for task, duration := range tasks {
+			total.Duration += duration
+			total.Tasks = append(total.Tasks, TaskTotal{Task: task, Duration: duration})
+		}
+		sort.Slice(total.Tasks, func(i, j int) bool {
+			return total.Tasks[i].Task < total.Tasks[j].Task
+		})
+		totals = append(totals, total)
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Project < totals[j].Project
+	})
+
+	return totals
+}
+
+func roundHours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
+
+func RenderProjectReportJSON(entries []peopleapi.ProjectTimesheet) ([]byte, error) {
+	totalsJSON := []ProjectTotalJSON{}
+
+	for _, total := range CalculateProjectTotals(entries) {
+		totalJSON := ProjectTotalJSON{
+			Project: total.Project,
+			Hours:   roundHours(total.Duration),
+			Tasks:   []TaskTotalJSON{},
+			Errors:  total.Errors,
+		}
+		for _, task := range total.Tasks {
+			totalJSON.Tasks = append(totalJSON.Tasks, TaskTotalJSON{Task: task.Task, Hours: roundHours(task.Duration)})
+		}
+		totalsJSON = append(totalsJSON, totalJSON)
+	}
+
+	return json.MarshalIndent(totalsJSON, "", "  ")
+}
+
+func RenderProjectReport(dateStart time.Time, dateEnd time.Time, entries []peopleapi.ProjectTimesheet, opts RenderOptions) string {
+	dimmed := color.New(color.Faint).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	var report strings.Builder
+
+	report.WriteString(dimmed("-----------------------------------------------\n"))
+
+	report.WriteString(color.CyanString("# Project report"))
+	report.WriteString("\n")
+
+	report.WriteString(dimmed("From : "))
+	report.WriteString(dateStart.Format(opts.dateFormat()))
+	report.WriteString("\n")
+	report.WriteString(dimmed("To   : "))
+	report.WriteString(dateEnd.Format(opts.dateFormat()))
+	report.WriteString("\n")
+
+	var total time.Duration
+
+	for _, project := range CalculateProjectTotals(entries) {
+		total += project.Duration
+
+		report.WriteString("\n")
+		report.WriteString(bold(fmt.Sprintf("%-30s %9s", project.Project, FormatDuration(project.Duration))))
+		report.WriteString("\n")
+
+		for _, task := range project.Tasks {
+			report.WriteString(fmt.Sprintf("  %-28s %9s\n", task.Task, FormatDuration(task.Duration)))
+		}
+
+		for _, err := range project.Errors {
+			report.WriteString(color.RedString("  Unreadable: %s", err))
+			report.WriteString("\n")
+		}
+	}
+
+	report.WriteString("\n")
+	report.WriteString(bold(fmt.Sprintf("%-30s %9s", "Total", FormatDuration(total))))
+	report.WriteString("\n")
+
+	report.WriteString(dimmed("\n-----------------------------------------------\n"))
+
+	return report.String()
+}
@@ -0,0 +1,48 @@
+package report_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+)
+
+func TestCalculateProjectTotals(t *testing.T) {
+	entries := []peopleapi.ProjectTimesheet{
+		{ProjectTimesheetDate: "2023-05-02", Project: "Acme", Task: "Dev", TotalHours: "02:00"},
+		{ProjectTimesheetDate: "2023-05-03", Project: "Acme", Task: "Dev", TotalHours: "1.5"},
+		{ProjectTimesheetDate: "2023-05-03", Project: "Acme", Task: "Calls", TotalHours: "00:30"},
+		{ProjectTimesheetDate: "2023-05-03", Project: "Beta", Task: "Dev", TotalHours: "03:15"},
+		{ProjectTimesheetDate: "2023-05-04", Project: "Beta", Task: "Dev", TotalHours: "broken"},
+		{ProjectTimesheetDate: "2023-05-05", Project: "Gamma", Task: "Ops", TotalHours: "-1"},
+	}
+
+	want := []report.ProjectTotal{
+		{
+			Project:  "Acme",
+			Duration: 4 * time.Hour,
+			Tasks: []report.TaskTotal{
+				{Task: "Calls", Duration: 30 * time.Minute},
+				{Task: "Dev", Duration: 3*time.Hour + 30*time.Minute},
+			},
+		},
+		{
+			Project:  "Beta",
+			Duration: 3*time.Hour + 15*time.Minute,
+			Tasks: []report.TaskTotal{
+				{Task: "Dev", Duration: 3*time.Hour + 15*time.Minute},
+			},
+			Errors: []string{`2023-05-04 Dev: invalid hours "broken"`},
+		},
+		{
+			Project: "Gamma",
+			Errors:  []string{`2023-05-05 Ops: invalid hours "-1"`},
+		},
+	}
+
+	if got := report.CalculateProjectTotals(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("CalculateProjectTotals() = %v, want %v", got, want)
+	}
+}
@@ -2,7 +2,9 @@ package report
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,75 +18,196 @@ type TimesheetDailyTotal struct {
 	Duration          time.Duration
 	IsComplete        bool
 	IsInvalidSequence bool
+	Intervals         []Interval
+	// FirstIn is the first check-in of the day, zero if there is none
+	FirstIn time.Time
+	// LastOut is the last check-out of the day, zero if there is none
+	LastOut time.Time
+	// Actions are the raw timesheet slots the total was calculated from
+	Actions []peopleapi.Action
+	// Error is why the slots or the date couldn't be read, the day counts no time then
+	Error string
+	// RawDate is the date as given when it can't be read, Date is zero then
+	RawDate string
+	// Day is the typed form of the slots, up to the offending slot of an invalid sequence
+	Day peopleapi.Day
+}
+
+// DateString returns the date as 2006-01-02, or as given when it can't be read
+func (t *TimesheetDailyTotal) DateString() string {
+	if t.RawDate != "" {
+		return t.RawDate
+	}
+	return t.Date.Format("2006-01-02")
+}
+
+// Breaks returns the gaps between consecutive work intervals.
+func (t *TimesheetDailyTotal) Breaks() []Interval {
+	var breaks []Interval
+
+	for i := 1; i < len(t.Intervals); i++ {
+		breaks = append(breaks, Interval{
+			Start: t.Intervals[i-1].End,
+			End:   t.Intervals[i].Start,
+		})
+	}
+
+	return breaks
+}
+
+// Interval is a single closed work interval: a check-in followed by a check-out.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the interval.
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
 }
 
 func CalculateHours(dayTimeSheet *peopleapi.TimeSheet) (*TimesheetDailyTotal, error) {
-	date, err := time.Parse("2006-01-02", dayTimeSheet.TimesheetDate)
+	date, err := peopleapi.ParseDate(dayTimeSheet.TimesheetDate)
 	if err != nil {
 		return nil, err
 	}
 
-	actionsList := peopleapi.TimeSheetToActionsList(dayTimeSheet)
-	var totalHours time.Duration
-
-	currentExpectedAction := peopleapi.ActionTypeIn
+	day, err := peopleapi.ParseDay(*dayTimeSheet)
+	isInvalidSequence := errors.Is(err, peopleapi.ErrInvalidSequence)
+	if err != nil && !isInvalidSequence {
+		return nil, err
+	}
 
-	currentTimeIn := time.Time{}
-	isComplete := false
+	total := &TimesheetDailyTotal{
+		Date:              date,
+		IsComplete:        !isInvalidSequence && len(day.Intervals) > 0 && !day.IsOpen(),
+		IsInvalidSequence: isInvalidSequence,
+		Actions:           peopleapi.TimeSheetToActionsList(dayTimeSheet),
+		Day:               day,
+	}
 
-	for _, action := range actionsList {
-		if action.Type != currentExpectedAction {
-			return &TimesheetDailyTotal{
-				Date:              date,
-				Duration:          totalHours,
-				IsComplete:        false,
-				IsInvalidSequence: true,
-			}, nil
+	for _, interval := range day.Intervals {
+		if total.FirstIn.IsZero() {
+			total.FirstIn = date.Add(interval.In)
+		}
+		if interval.Open {
+			continue
 		}
 
-		if currentExpectedAction == peopleapi.ActionTypeIn {
-			currentTimeIn, err = time.Parse("15:04:05", action.Time)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			timeOut, err := time.Parse("15:04:05", action.Time)
-			if err != nil {
-				return nil, err
-			}
+		total.Duration += interval.Duration()
+		total.Intervals = append(total.Intervals, Interval{
+			Start: date.Add(interval.In),
+			End:   date.Add(interval.End()),
+		})
+		total.LastOut = date.Add(interval.End())
+	}
 
-			totalHours += timeOut.Sub(currentTimeIn)
-		}
+	return total, nil
+}
 
-		if currentExpectedAction == peopleapi.ActionTypeIn {
-			currentExpectedAction = peopleapi.ActionTypeOut
-			isComplete = false
-		} else {
-			currentExpectedAction = peopleapi.ActionTypeIn
-			isComplete = true
-		}
+// CheckedInSince returns the check-in of the interval which is still open,
+// ok is false if the day is checked out or its sequence is invalid
+func (t *TimesheetDailyTotal) CheckedInSince() (since time.Time, ok bool) {
+	if t.IsInvalidSequence || t.Error != "" || !t.Day.IsOpen() {
+		return time.Time{}, false
 	}
 
-	return &TimesheetDailyTotal{
-		Date:              date,
-		Duration:          totalHours,
-		IsComplete:        isComplete,
-		IsInvalidSequence: false,
-	}, nil
+	open := t.Day.Intervals[len(t.Day.Intervals)-1]
+
+	return t.Date.Add(open.In), true
 }
 
-func RenderDailyReportJSON(dateStart time.Time, dateEnd time.Time, timeSheets []peopleapi.TimeSheet) ([]byte, error) {
-	totals := []TimesheetDailyTotalJSON{}
+// WorkedUpTo returns the time worked up to the clock time of t,
+// counting the interval which is still open
+func (t *TimesheetDailyTotal) WorkedUpTo(clock time.Time) time.Duration {
+	worked := t.Duration
+
+	since, ok := t.CheckedInSince()
+	if !ok {
+		return worked
+	}
+
+	if open := atDate(t.Date, clock).Sub(since); open > 0 {
+		worked += open
+	}
+
+	return worked
+}
+
+// atDate places the clock time of t onto the given date
+func atDate(date time.Time, t time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), 0, date.Location())
+}
+
+// calculateTotals computes the daily totals of the timesheets.
+// A day whose slots can't be read gets a total with the Error set,
+// one whose date can't be read also keeps the date as given in RawDate and comes last.
+func calculateTotals(timeSheets []peopleapi.TimeSheet) []TimesheetDailyTotal {
+	totals := []TimesheetDailyTotal{}
 
 	for _, timeSheet := range timeSheets {
 		timesheetDailyTotal, err := CalculateHours(&timeSheet)
 		if err != nil {
-			continue
+			timesheetDailyTotal = &TimesheetDailyTotal{
+				Actions: peopleapi.TimeSheetToActionsList(&timeSheet),
+				Error:   err.Error(),
+			}
+			date, dateErr := peopleapi.ParseDate(timeSheet.TimesheetDate)
+			if dateErr != nil {
+				timesheetDailyTotal.RawDate = timeSheet.TimesheetDate
+			}
+			timesheetDailyTotal.Date = date
 		}
 
-		totals = append(totals, NewTimesheetDailyTotalJSON(timesheetDailyTotal))
+		totals = append(totals, *timesheetDailyTotal)
+	}
+
+	sort.SliceStable(totals, func(i, j int) bool {
+		if (totals[i].RawDate != "") != (totals[j].RawDate != "") {
+			return totals[j].RawDate != ""
+		}
+		return totals[i].Date.Before(totals[j].Date)
+	})
+
+	return totals
+}
+
+func RenderDailyReportJSON(dateStart time.Time, dateEnd time.Time, timeSheets []peopleapi.TimeSheet, opts RenderOptions) ([]byte, error) {
+	totals := []TimesheetDailyTotalJSON{}
+
+	dailyTotals := calculateTotals(timeSheets)
+
+	var violations map[string][]Violation
+	if opts.Compliance != nil {
+		violations = CheckCompliance(dailyTotals, *opts.Compliance)
 	}
 
+	withTimesheet := make(map[string]bool)
+	for _, timesheetDailyTotal := range dailyTotals {
+		totalJSON := NewTimesheetDailyTotalJSON(&timesheetDailyTotal)
+		totalJSON.Violations = violations[totalJSON.Date]
+		totalJSON.IsHoliday = opts.Holidays[totalJSON.Date]
+		totals = append(totals, totalJSON)
+		withTimesheet[totalJSON.Date] = true
+	}
+
+	// holidays without work have no timesheet, they are listed as non-working days all the same
+	for date := dateStart; date.Before(dateEnd); date = date.AddDate(0, 0, 1) {
+		key := date.Format("2006-01-02")
+		if opts.Holidays[key] && !withTimesheet[key] {
+			totals = append(totals, TimesheetDailyTotalJSON{
+				Date:      key,
+				Intervals: []IntervalJSON{},
+				Breaks:    []IntervalJSON{},
+				IsHoliday: true,
+			})
+		}
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Date < totals[j].Date
+	})
+
 	jsonData, err := json.MarshalIndent(totals, "", "  ")
 	if err != nil {
 		return []byte{}, err
@@ -93,19 +216,57 @@ func RenderDailyReportJSON(dateStart time.Time, dateEnd time.Time, timeSheets []
 	return jsonData, nil
 }
 
-func RenderDailyReport(dateStart time.Time, dateEnd time.Time, timeSheets []peopleapi.TimeSheet) string {
+// RenderOptions control the text report output
+type RenderOptions struct {
+	// Detailed lists the intervals, breaks and raw slots of every day
+	Detailed bool
+	// Compliance enables the working-time rules check when not nil
+	Compliance *ComplianceRules
+	// DateFormat is the Go layout of the range heading, "02-Jan-2006" if empty
+	DateFormat string
+	// DayFormat is the Go layout of the date of every line, "02-Jan" if empty
+	DayFormat string
+	// Holidays are the YYYY-MM-DD dates of approved holidays, shown as non-working days
+	Holidays map[string]bool
+	// DailyTarget shows the difference of every complete day to it, if set
+	DailyTarget time.Duration
+}
+
+func (o RenderOptions) dateFormat() string {
+	if o.DateFormat == "" {
+		return "02-Jan-2006"
+	}
+	return o.DateFormat
+}
+
+func (o RenderOptions) dayFormat() string {
+	if o.DayFormat == "" {
+		return "02-Jan"
+	}
+	return o.DayFormat
+}
+
+func RenderDailyReport(dateStart time.Time, dateEnd time.Time, timeSheets []peopleapi.TimeSheet, opts RenderOptions) string {
 	dimmed := color.New(color.Faint).SprintFunc()
 	bold := color.New(color.Bold).SprintFunc()
 
 	perDateTotals := make(map[string]TimesheetDailyTotal)
 
-	for _, timeSheet := range timeSheets {
-		timesheetDailyTotal, err := CalculateHours(&timeSheet)
-		if err != nil {
+	// timesheets whose date can't be read are listed after the range
+	var unreadableDates []TimesheetDailyTotal
+
+	dailyTotals := calculateTotals(timeSheets)
+	for _, timesheetDailyTotal := range dailyTotals {
+		if timesheetDailyTotal.RawDate != "" {
+			unreadableDates = append(unreadableDates, timesheetDailyTotal)
 			continue
 		}
+		perDateTotals[timesheetDailyTotal.Date.Format("2006-01-02")] = timesheetDailyTotal
+	}
 
-		perDateTotals[timesheetDailyTotal.Date.Format("2006-01-02")] = *timesheetDailyTotal
+	var violations map[string][]Violation
+	if opts.Compliance != nil {
+		violations = CheckCompliance(dailyTotals, *opts.Compliance)
 	}
 
 	var report strings.Builder
@@ -116,30 +277,48 @@ func RenderDailyReport(dateStart time.Time, dateEnd time.Time, timeSheets []peop
 	report.WriteString("\n")
 
 	report.WriteString(dimmed("From : "))
-	report.WriteString(dateStart.Format("02-Jan-2006"))
+	report.WriteString(dateStart.Format(opts.dateFormat()))
 	report.WriteString("\n")
 	report.WriteString(dimmed("To   : "))
-	report.WriteString(dateEnd.Format("02-Jan-2006"))
+	report.WriteString(dateEnd.Format(opts.dateFormat()))
 
 	report.WriteString("\n")
 	report.WriteString("\n")
 
 	for date := dateStart; date.Before(dateEnd); date = date.AddDate(0, 0, 1) {
-		report.WriteString(date.Format("02-Jan"))
+		report.WriteString(date.Format(opts.dayFormat()))
 		report.WriteString(" ")
 		report.WriteString(renderWeekDay(date))
 		report.WriteString(": ")
 
+		isHoliday := opts.Holidays[date.Format("2006-01-02")]
+
 		timesheetDailyTotal, ok := perDateTotals[date.Format("2006-01-02")]
 		if !ok {
-			report.WriteString(dimmed("-"))
+			if isHoliday {
+				report.WriteString(color.BlueString("Holiday"))
+			} else {
+				report.WriteString(dimmed("-"))
+			}
+			report.WriteString("\n")
+			continue
+		}
+
+		if timesheetDailyTotal.Error != "" {
+			report.WriteString(color.RedString("Unreadable: %s", timesheetDailyTotal.Error))
 			report.WriteString("\n")
+			if opts.Detailed {
+				renderDayDetails(&report, &timesheetDailyTotal)
+			}
 			continue
 		}
 
 		if timesheetDailyTotal.IsInvalidSequence {
 			report.WriteString(color.RedString("Invalid sequence"))
 			report.WriteString("\n")
+			if opts.Detailed {
+				renderDayDetails(&report, &timesheetDailyTotal)
+			}
 			continue
 		}
 
@@ -147,8 +326,36 @@ func RenderDailyReport(dateStart time.Time, dateEnd time.Time, timeSheets []peop
 		if !timesheetDailyTotal.IsComplete {
 			report.WriteString(" ")
 			report.WriteString(color.YellowString("(incomplete)"))
+		} else if opts.DailyTarget > 0 {
+			report.WriteString(" ")
+			report.WriteString(renderTargetDiff(timesheetDailyTotal.Duration - opts.DailyTarget))
+		}
+		if isHoliday {
+			report.WriteString(" ")
+			report.WriteString(color.BlueString("(holiday)"))
+		}
+		if len(violations[date.Format("2006-01-02")]) > 0 {
+			report.WriteString(" ")
+			report.WriteString(color.RedString("(!)"))
 		}
 		report.WriteString("\n")
+
+		if opts.Detailed {
+			renderDayDetails(&report, &timesheetDailyTotal)
+		}
+	}
+
+	for _, timesheetDailyTotal := range unreadableDates {
+		report.WriteString(fmt.Sprintf("%q: ", timesheetDailyTotal.RawDate))
+		report.WriteString(color.RedString("Unreadable: %s", timesheetDailyTotal.Error))
+		report.WriteString("\n")
+		if opts.Detailed {
+			renderDayDetails(&report, &timesheetDailyTotal)
+		}
+	}
+
+	if opts.Compliance != nil {
+		renderCompliance(&report, dateStart, dateEnd, opts, violations)
 	}
 
 	report.WriteString(dimmed("\n-----------------------------------------------\n"))
@@ -156,6 +363,72 @@ func RenderDailyReport(dateStart time.Time, dateEnd time.Time, timeSheets []peop
 	return report.String()
 }
 
+// renderTargetDiff renders the difference to the daily target, e.g. "(+0h15m)"
+func renderTargetDiff(diff time.Duration) string {
+	diff = diff.Round(time.Minute)
+	if diff < 0 {
+		return color.RedString("(-%s)", FormatDuration(-diff))
+	}
+	return color.GreenString("(+%s)", FormatDuration(diff))
+}
+
+func renderCompliance(report *strings.Builder, dateStart time.Time, dateEnd time.Time, opts RenderOptions, violations map[string][]Violation) {
+	report.WriteString("\n")
+	report.WriteString(color.CyanString(fmt.Sprintf("# Compliance (%s)", opts.Compliance.Name)))
+	report.WriteString("\n")
+
+	count := 0
+	for date := dateStart; date.Before(dateEnd); date = date.AddDate(0, 0, 1) {
+		for _, violation := range violations[date.Format("2006-01-02")] {
+			report.WriteString(date.Format(opts.dayFormat()))
+			report.WriteString(" ")
+			report.WriteString(renderWeekDay(date))
+			report.WriteString(": ")
+			report.WriteString(color.RedString(violation.Rule))
+			report.WriteString(" - ")
+			report.WriteString(violation.Message)
+			report.WriteString("\n")
+			count++
+		}
+	}
+
+	if count == 0 {
+		report.WriteString(color.GreenString("No violations"))
+		report.WriteString("\n")
+	}
+}
+
+func renderDayDetails(report *strings.Builder, total *TimesheetDailyTotal) {
+	dimmed := color.New(color.Faint).SprintFunc()
+
+	for i, interval := range total.Intervals {
+		if i > 0 {
+			brk := Interval{Start: total.Intervals[i-1].End, End: interval.Start}
+			report.WriteString(dimmed(fmt.Sprintf("    break %s - %s  %s\n",
+				brk.Start.Format("15:04"), brk.End.Format("15:04"), FormatDuration(brk.Duration()))))
+		}
+		report.WriteString(fmt.Sprintf("    work  %s - %s  %s\n",
+			interval.Start.Format("15:04"), interval.End.Format("15:04"), FormatDuration(interval.Duration())))
+	}
+
+	if !total.FirstIn.IsZero() {
+		report.WriteString(dimmed("    first in : "))
+		report.WriteString(total.FirstIn.Format("15:04"))
+		report.WriteString("\n")
+	}
+	if !total.LastOut.IsZero() {
+		report.WriteString(dimmed("    last out : "))
+		report.WriteString(total.LastOut.Format("15:04"))
+		report.WriteString("\n")
+	}
+
+	report.WriteString(dimmed("    slots    :"))
+	for _, action := range total.Actions {
+		report.WriteString(dimmed(fmt.Sprintf(" %s=%s", action.Slot, action.Time)))
+	}
+	report.WriteString("\n")
+}
+
 func renderWeekDay(date time.Time) string {
 
 	str := date.Format("Mon")
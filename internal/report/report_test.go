@@ -1,7 +1,9 @@
 package report_test
 
 import (
+	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -35,6 +37,22 @@ func TestCalculateHours(t *testing.T) {
 				Duration:          8 * time.Hour,
 				IsComplete:        true,
 				IsInvalidSequence: false,
+				Intervals: []report.Interval{
+					{Start: time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC), End: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)},
+					{Start: time.Date(2020, 1, 1, 13, 0, 0, 0, time.UTC), End: time.Date(2020, 1, 1, 17, 0, 0, 0, time.UTC)},
+				},
+				FirstIn: time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC),
+				LastOut: time.Date(2020, 1, 1, 17, 0, 0, 0, time.UTC),
+				Actions: []peopleapi.Action{
+					{Slot: "TimeIn1", Type: peopleapi.ActionTypeIn, Time: "08:00:00"},
+					{Slot: "TimeOut1", Type: peopleapi.ActionTypeOut, Time: "12:00:00"},
+					{Slot: "TimeIn2", Type: peopleapi.ActionTypeIn, Time: "13:00:00"},
+					{Slot: "TimeOut2", Type: peopleapi.ActionTypeOut, Time: "17:00:00"},
+				},
+				Day: peopleapi.Day{
+					Date:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					Intervals: []peopleapi.Interval{{Pair: 1, In: 8 * time.Hour, Out: 12 * time.Hour}, {Pair: 2, In: 13 * time.Hour, Out: 17 * time.Hour}},
+				},
 			},
 		},
 		{
@@ -55,6 +73,25 @@ func TestCalculateHours(t *testing.T) {
 				Duration:          8 * time.Hour,
 				IsComplete:        true,
 				IsInvalidSequence: false,
+				Intervals: []report.Interval{
+					{Start: time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC), End: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)},
+					{Start: time.Date(2020, 1, 1, 13, 0, 0, 0, time.UTC), End: time.Date(2020, 1, 1, 14, 0, 0, 0, time.UTC)},
+					{Start: time.Date(2020, 1, 1, 15, 0, 0, 0, time.UTC), End: time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC)},
+				},
+				FirstIn: time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC),
+				LastOut: time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC),
+				Actions: []peopleapi.Action{
+					{Slot: "TimeIn1", Type: peopleapi.ActionTypeIn, Time: "08:00:00"},
+					{Slot: "TimeOut1", Type: peopleapi.ActionTypeOut, Time: "12:00:00"},
+					{Slot: "TimeIn2", Type: peopleapi.ActionTypeIn, Time: "13:00:00"},
+					{Slot: "TimeOut2", Type: peopleapi.ActionTypeOut, Time: "14:00:00"},
+					{Slot: "TimeIn3", Type: peopleapi.ActionTypeIn, Time: "15:00:00"},
+					{Slot: "TimeOut3", Type: peopleapi.ActionTypeOut, Time: "18:00:00"},
+				},
+				Day: peopleapi.Day{
+					Date:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					Intervals: []peopleapi.Interval{{Pair: 1, In: 8 * time.Hour, Out: 12 * time.Hour}, {Pair: 2, In: 13 * time.Hour, Out: 14 * time.Hour}, {Pair: 3, In: 15 * time.Hour, Out: 18 * time.Hour}},
+				},
 			},
 		},
 		{
@@ -72,6 +109,20 @@ func TestCalculateHours(t *testing.T) {
 				Duration:          4 * time.Hour,
 				IsComplete:        false,
 				IsInvalidSequence: false,
+				Intervals: []report.Interval{
+					{Start: time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC), End: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)},
+				},
+				FirstIn: time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC),
+				LastOut: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+				Actions: []peopleapi.Action{
+					{Slot: "TimeIn1", Type: peopleapi.ActionTypeIn, Time: "08:00:00"},
+					{Slot: "TimeOut1", Type: peopleapi.ActionTypeOut, Time: "12:00:00"},
+					{Slot: "TimeIn2", Type: peopleapi.ActionTypeIn, Time: "13:00:00"},
+				},
+				Day: peopleapi.Day{
+					Date:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					Intervals: []peopleapi.Interval{{Pair: 1, In: 8 * time.Hour, Out: 12 * time.Hour}, {Pair: 2, In: 13 * time.Hour, Open: true}},
+				},
 			},
 		},
 		{
@@ -88,6 +139,73 @@ func TestCalculateHours(t *testing.T) {
 				Duration:          0 * time.Hour,
 				IsComplete:        false,
 				IsInvalidSequence: true,
+				FirstIn:           time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC),
+				Actions: []peopleapi.Action{
+					{Slot: "TimeIn1", Type: peopleapi.ActionTypeIn, Time: "08:00:00"},
+					{Slot: "TimeIn2", Type: peopleapi.ActionTypeIn, Time: "09:00:00"},
+				},
+				Day: peopleapi.Day{
+					Date:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					Intervals: []peopleapi.Interval{{Pair: 1, In: 8 * time.Hour, Open: true}},
+				},
+			},
+		},
+		{
+			name: "shift across midnight",
+			args: args{
+				dayTimeSheet: &peopleapi.TimeSheet{
+					TimesheetDate: "2020-01-01",
+					TimeIn1:       "22:00:00",
+					TimeOut1:      "02:30:00",
+				},
+			},
+			want: &report.TimesheetDailyTotal{
+				Date:              time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				Duration:          4*time.Hour + 30*time.Minute,
+				IsComplete:        true,
+				IsInvalidSequence: false,
+				Intervals: []report.Interval{
+					{Start: time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC), End: time.Date(2020, 1, 2, 2, 30, 0, 0, time.UTC)},
+				},
+				FirstIn: time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC),
+				LastOut: time.Date(2020, 1, 2, 2, 30, 0, 0, time.UTC),
+				Actions: []peopleapi.Action{
+					{Slot: "TimeIn1", Type: peopleapi.ActionTypeIn, Time: "22:00:00"},
+					{Slot: "TimeOut1", Type: peopleapi.ActionTypeOut, Time: "02:30:00"},
+				},
+				Day: peopleapi.Day{
+					Date:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					Intervals: []peopleapi.Interval{{Pair: 1, In: 22 * time.Hour, Out: 2*time.Hour + 30*time.Minute}},
+				},
+			},
+		},
+		{
+			name: "mixed time formats",
+			args: args{
+				dayTimeSheet: &peopleapi.TimeSheet{
+					TimesheetDate: "2020-01-01",
+					TimeIn1:       "8:00",
+					TimeOut1:      "12:30 PM",
+				},
+			},
+			want: &report.TimesheetDailyTotal{
+				Date:              time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				Duration:          4*time.Hour + 30*time.Minute,
+				IsComplete:        true,
+				IsInvalidSequence: false,
+				Intervals: []report.Interval{
+					{Start: time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC), End: time.Date(2020, 1, 1, 12, 30, 0, 0, time.UTC)},
+				},
+				FirstIn: time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC),
+				LastOut: time.Date(2020, 1, 1, 12, 30, 0, 0, time.UTC),
+				Actions: []peopleapi.Action{
+					{Slot: "TimeIn1", Type: peopleapi.ActionTypeIn, Time: "8:00"},
+					{Slot: "TimeOut1", Type: peopleapi.ActionTypeOut, Time: "12:30 PM"},
+				},
+				Day: peopleapi.Day{
+					Date:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					Intervals: []peopleapi.Interval{{Pair: 1, In: 8 * time.Hour, Out: 12*time.Hour + 30*time.Minute}},
+				},
 			},
 		},
 		{
@@ -127,3 +245,114 @@ func TestCalculateHours(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderDailyReportJSONUnreadableDay(t *testing.T) {
+	timeSheets := []peopleapi.TimeSheet{
+		{TimesheetDate: "2020-01-01", TimeIn1: "08:00:00", TimeOut1: "12:00:00"},
+		{TimesheetDate: "2020-01-02", TimeIn1: "soon", TimeOut1: "12:00:00"},
+		{TimesheetDate: "someday", TimeIn1: "08:00:00", TimeOut1: "12:00:00"},
+		{TimesheetDate: "2020-01-03T00:00:00", TimeIn1: "08:00:00", TimeOut1: "10:00:00"},
+	}
+
+	data, err := report.RenderDailyReportJSON(time.Time{}, time.Time{}, timeSheets, report.RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var days []report.TimesheetDailyTotalJSON
+	if err := json.Unmarshal(data, &days); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(days) != 4 {
+		t.Fatalf("RenderDailyReportJSON() has %d days, want the unreadable ones too", len(days))
+	}
+	if days[0].Error != "" || days[0].Hours != 4 {
+		t.Errorf("RenderDailyReportJSON() day 1 = %+v, want 4 hours", days[0])
+	}
+	if days[1].Date != "2020-01-02" || !strings.Contains(days[1].Error, "TimeIn1") {
+		t.Errorf("RenderDailyReportJSON() day 2 = %+v, want an error about TimeIn1", days[1])
+	}
+	if days[2].Date != "2020-01-03" || days[2].Hours != 2 {
+		t.Errorf("RenderDailyReportJSON() day 3 = %+v, want 2 hours on 2020-01-03", days[2])
+	}
+	if days[3].Date != "someday" || !strings.Contains(days[3].Error, `"someday"`) || days[3].Hours != 0 {
+		t.Errorf("RenderDailyReportJSON() day 4 = %+v, want an error labelled with the date as given", days[3])
+	}
+}
+
+func TestRenderDailyReportJSONHolidays(t *testing.T) {
+	timeSheets := []peopleapi.TimeSheet{
+		{TimesheetDate: "2020-01-02", TimeIn1: "08:00:00", TimeOut1: "12:00:00"},
+	}
+	opts := report.RenderOptions{Holidays: map[string]bool{"2020-01-01": true, "2020-01-02": true, "2020-01-09": true}}
+
+	data, err := report.RenderDailyReportJSON(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC), timeSheets, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var days []report.TimesheetDailyTotalJSON
+	if err := json.Unmarshal(data, &days); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(days) != 2 {
+		t.Fatalf("RenderDailyReportJSON() = %+v, want the holiday without work and the worked one", days)
+	}
+	if days[0].Date != "2020-01-01" || !days[0].IsHoliday || days[0].Hours != 0 {
+		t.Errorf("RenderDailyReportJSON() day 1 = %+v, want a holiday without work", days[0])
+	}
+	if days[1].Date != "2020-01-02" || !days[1].IsHoliday || days[1].Hours != 4 {
+		t.Errorf("RenderDailyReportJSON() day 2 = %+v, want a holiday with 4 hours", days[1])
+	}
+}
+
+func TestWorkedUpTo(t *testing.T) {
+	clock := time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		timeSheet peopleapi.TimeSheet
+		want      time.Duration
+	}{
+		{
+			name: "checked in",
+			timeSheet: peopleapi.TimeSheet{
+				TimesheetDate: "2020-01-01",
+				TimeIn1:       "08:00:00",
+				TimeOut1:      "12:00:00",
+				TimeIn2:       "13:00:00",
+			},
+			want: 6*time.Hour + 30*time.Minute,
+		},
+		{
+			name: "checked out",
+			timeSheet: peopleapi.TimeSheet{
+				TimesheetDate: "2020-01-01",
+				TimeIn1:       "08:00:00",
+				TimeOut1:      "12:00:00",
+			},
+			want: 4 * time.Hour,
+		},
+		{
+			name: "checked in later than the clock",
+			timeSheet: peopleapi.TimeSheet{
+				TimesheetDate: "2020-01-01",
+				TimeIn1:       "16:00:00",
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, err := report.CalculateHours(&tt.timeSheet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := total.WorkedUpTo(clock); got != tt.want {
+				t.Errorf("WorkedUpTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+// MemberSummary is the part of the team report about one employee
+type MemberSummary struct {
+	EmployeeID string
+	Total      time.Duration
+	Days       int
+	// MissingCheckouts are the dates of days left checked in, today excluded
+	MissingCheckouts []string
+	InvalidSequences []string
+	// Unreadable are the dates of days with slots which can't be read
+	Unreadable []string
+	// Error is set when the timesheets of the employee couldn't be fetched
+	Error string
+}
+
+type MemberSummaryJSON struct {
+	EmployeeID       string   `json:"employee_id"`
+	Hours            float64  `json:"hours"`
+	Days             int      `json:"days"`
+	MissingCheckouts []string `json:"missing_checkouts"`
+	InvalidSequences []string `json:"invalid_sequences"`
+	Unreadable       []string `json:"unreadable"`
+	Error            string   `json:"error,omitempty"`
+}
+
+// SummarizeTeam sums up the timesheets of every team member.
+// A day left checked in is a missing check-out unless it is today.
+func SummarizeTeam(members []peopleapi.MemberTimesheets, now time.Time) []MemberSummary {
+	today := now.Format("2006-01-02")
+
+	summaries := []MemberSummary{}
+
+	for _, member := range members {
+		summary := MemberSummary{
+			EmployeeID:       member.EmployeeID,
+			MissingCheckouts: []string{},
+			InvalidSequences: []string{},
+			Unreadable:       []string{},
+		}
+
+		if member.Err != nil {
+			summary.Error = member.Err.Error()
+			summaries = append(summaries, summary)
+			continue
+		}
+
+		for _, total := range calculateTotals(member.TimeSheets) {
+			date := total.DateString()
+
+			switch {
+			case total.Error != "":
+				summary.Unreadable = append(summary.Unreadable, date)
+			case total.IsInvalidSequence:
+				summary.InvalidSequences = append(summary.InvalidSequences, date)
+			case !total.IsComplete && date != today:
+				summary.MissingCheckouts = append(summary.MissingCheckouts, date)
+			}
+
+			if len(total.Actions) > 0 {
+				summary.Days++
+			}
+			summary.Total += total.Duration
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries
+}
+
+func RenderTeamReportJSON(summaries []MemberSummary) ([]byte, error) {
+	summariesJSON := []MemberSummaryJSON{}
+
+	for _, summary := range summaries {
+		summariesJSON = append(summariesJSON, MemberSummaryJSON{
+			EmployeeID:       summary.EmployeeID,
+			Hours:            math.Round(summary.Total.Hours()*100) / 100,
+			Days:             summary.Days,
+			MissingCheckouts: summary.MissingCheckouts,
+			InvalidSequences: summary.InvalidSequences,
+			Unreadable:       summary.Unreadable,
+			Error:            summary.Error,
+		})
+	}
+
+	return json.MarshalIndent(summariesJSON, "", "  ")
+}
+
+// RenderTeamReportCSV renders one row per employee, the dates are separated by spaces
+func RenderTeamReportCSV(summaries []MemberSummary) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{{"employee_id", "hours", "days", "missing_checkouts", "invalid_sequences", "unreadable", "error"}}
+	for _, summary := range summaries {
+		rows = append(rows, []string{
+			summary.EmployeeID,
+			strconv.FormatFloat(math.Round(summary.Total.Hours()*100)/100, 'f', -1, 64),
+			strconv.Itoa(summary.Days),
+			strings.Join(summary.MissingCheckouts, " "),
+			strings.Join(summary.InvalidSequences, " "),
+			strings.Join(summary.Unreadable, " "),
+			summary.Error,
+		})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func RenderTeamReport(dateStart time.Time, dateEnd time.Time, summaries []MemberSummary, opts RenderOptions) string {
+	dimmed := color.New(color.Faint).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	var report strings.Builder
+
+	report.WriteString(dimmed("-----------------------------------------------\n"))
+
+	report.WriteString(color.CyanString("# Team report"))
+	report.WriteString("\n")
+
+	report.WriteString(dimmed("From : "))
+	report.WriteString(dateStart.Format(opts.dateFormat()))
+	report.WriteString("\n")
+	report.WriteString(dimmed("To   : "))
+	report.WriteString(dateEnd.Format(opts.dateFormat()))
+	report.WriteString("\n")
+
+	for _, summary := range summaries {
+		report.WriteString("\n")
+
+		if summary.Error != "" {
+			report.WriteString(fmt.Sprintf("%-15s ", summary.EmployeeID))
+			report.WriteString(color.RedString(summary.Error))
+			report.WriteString("\n")
+			continue
+		}
+
+		report.WriteString(bold(fmt.Sprintf("%-15s %9s", summary.EmployeeID, FormatDuration(summary.Total))))
+		report.WriteString(dimmed(fmt.Sprintf("  %d day(s)", summary.Days)))
+		report.WriteString("\n")
+
+		for _, date := range summary.MissingCheckouts {
+			report.WriteString("  ")
+			report.WriteString(renderTeamDate(date, opts))
+			report.WriteString(color.YellowString("missing check-out"))
+			report.WriteString("\n")
+		}
+		for _, date := range summary.InvalidSequences {
+			report.WriteString("  ")
+			report.WriteString(renderTeamDate(date, opts))
+			report.WriteString(color.RedString("invalid sequence"))
+			report.WriteString("\n")
+		}
+		for _, date := range summary.Unreadable {
+			report.WriteString("  ")
+			report.WriteString(renderTeamDate(date, opts))
+			report.WriteString(color.RedString("unreadable"))
+			report.WriteString("\n")
+		}
+	}
+
+	report.WriteString(dimmed("\n-----------------------------------------------\n"))
+
+	return report.String()
+}
+
+func renderTeamDate(date string, opts RenderOptions) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date + ": "
+	}
+	return t.Format(opts.dayFormat()) + " " + renderWeekDay(t) + ": "
+}
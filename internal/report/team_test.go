@@ -0,0 +1,84 @@
+package report_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+)
+
+func TestSummarizeTeam(t *testing.T) {
+	now := time.Date(2023, 5, 3, 10, 0, 0, 0, time.UTC)
+
+	members := []peopleapi.MemberTimesheets{
+		{
+			EmployeeID: "PW1",
+			TimeSheets: []peopleapi.TimeSheet{
+				{TimesheetDate: "2023-05-01", TimeIn1: "09:00:00", TimeOut1: "17:00:00"},
+				{TimesheetDate: "2023-05-02", TimeIn1: "09:00:00"},
+				{TimesheetDate: "2023-05-03", TimeIn1: "09:00:00"},
+			},
+		},
+		{
+			EmployeeID: "PW2",
+			TimeSheets: []peopleapi.TimeSheet{
+				{TimesheetDate: "2023-05-01", TimeIn1: "08:00:00", TimeOut1: "12:00:00", TimeOut2: "13:00:00"},
+				{TimesheetDate: "2023-05-02", TimeIn1: "soon", TimeOut1: "12:00:00"},
+				{TimesheetDate: "May 3rd", TimeIn1: "08:00:00", TimeOut1: "12:00:00"},
+			},
+		},
+		{
+			EmployeeID: "PW3",
+			Err:        errors.New("server response: Invalid Employee Id"),
+		},
+	}
+
+	want := []report.MemberSummary{
+		{
+			EmployeeID:       "PW1",
+			Total:            8 * time.Hour,
+			Days:             3,
+			MissingCheckouts: []string{"2023-05-02"},
+			InvalidSequences: []string{},
+			Unreadable:       []string{},
+		},
+		{
+			EmployeeID:       "PW2",
+			Total:            4 * time.Hour,
+			Days:             3,
+			MissingCheckouts: []string{},
+			InvalidSequences: []string{"2023-05-01"},
+			Unreadable:       []string{"2023-05-02", "May 3rd"},
+		},
+		{
+			EmployeeID:       "PW3",
+			MissingCheckouts: []string{},
+			InvalidSequences: []string{},
+			Unreadable:       []string{},
+			Error:            "server response: Invalid Employee Id",
+		},
+	}
+
+	if got := report.SummarizeTeam(members, now); !reflect.DeepEqual(got, want) {
+		t.Errorf("SummarizeTeam() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRenderTeamReportCSV(t *testing.T) {
+	summaries := []report.MemberSummary{
+		{EmployeeID: "PW1", Total: 7*time.Hour + 30*time.Minute, Days: 1, MissingCheckouts: []string{"2023-05-02", "2023-05-04"}},
+	}
+
+	got, err := report.RenderTeamReportCSV(summaries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "employee_id,hours,days,missing_checkouts,invalid_sequences,unreadable,error\nPW1,7.5,1,2023-05-02 2023-05-04,,,\n"
+	if string(got) != want {
+		t.Errorf("RenderTeamReportCSV() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+// TemplateData is the data model passed to user-defined report templates.
+//
+//   - Start, End - the report range
+//   - Days       - the daily totals, ordered by date, with their intervals;
+//     the ones whose date can't be read come last, with RawDate set
+//   - Weeks      - the weekly subtotals, ordered by week
+//   - Total      - the total time worked in the range
+type TemplateData struct {
+	Start time.Time
+	End   time.Time
+	Days  []TimesheetDailyTotal
+	Weeks []WeeklySubtotal
+	Total time.Duration
+}
+
+// WeeklySubtotal is the time worked during a single ISO week.
+type WeeklySubtotal struct {
+	Year     int
+	Week     int
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	Days     []TimesheetDailyTotal
+}
+
+// NewTemplateData builds the template data model for the given range.
+func NewTemplateData(dateStart time.Time, dateEnd time.Time, timeSheets []peopleapi.TimeSheet) TemplateData {
+	data := TemplateData{
+		Start: dateStart,
+		End:   dateEnd,
+		Days:  calculateTotals(timeSheets),
+		Weeks: []WeeklySubtotal{},
+	}
+
+	for _, day := range data.Days {
+		data.Total += day.Duration
+
+		// a day whose date can't be read belongs to no week, it comes last
+		if day.RawDate != "" {
+			continue
+		}
+
+		year, week := day.Date.ISOWeek()
+		last := len(data.Weeks) - 1
+		if last < 0 || data.Weeks[last].Year != year || data.Weeks[last].Week != week {
+			weekStart := day.Date.AddDate(0, 0, -((int(day.Date.Weekday()) + 6) % 7))
+			data.Weeks = append(data.Weeks, WeeklySubtotal{
+				Year:  year,
+				Week:  week,
+				Start: weekStart,
+				End:   weekStart.AddDate(0, 0, 6),
+			})
+			last++
+		}
+
+		data.Weeks[last].Duration += day.Duration
+		data.Weeks[last].Days = append(data.Weeks[last].Days, day)
+	}
+
+	return data
+}
+
+// TemplateFuncs returns the helper functions available in report templates.
+//
+//   - duration - formats a duration as "3h12m"
+//   - hours    - formats a duration as decimal hours, e.g. "3.2"
+//   - date     - formats a time using a Go layout: {{ date "02-Jan" .Date }}
+//   - clock    - formats a time as "15:04"
+//   - weekday  - returns the short weekday name, e.g. "Mon"
+//   - isWeekend - reports whether the time falls on Saturday or Sunday
+func TemplateFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"duration": FormatDuration,
+		"hours": func(d time.Duration) string {
+			return fmt.Sprintf("%.1f", d.Hours())
+		},
+		"date": func(layout string, t time.Time) string {
+			return t.Format(layout)
+		},
+		"clock": func(t time.Time) string {
+			return t.Format("15:04")
+		},
+		"weekday": func(t time.Time) string {
+			return t.Format("Mon")
+		},
+		"isWeekend": func(t time.Time) bool {
+			return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+		},
+	}
+}
+
+// FormatDuration formats a duration as hours and minutes, e.g. "3h12m"
+func FormatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%s%dh%02dm", sign, int(d.Hours()), int(d.Minutes())%60)
+}
+
+// RenderTemplateReport renders the report through a user-defined template.
+// Files with the .html or .htm extension are rendered with html/template,
+// everything else with text/template.
+func RenderTemplateReport(dateStart time.Time, dateEnd time.Time, timeSheets []peopleapi.TimeSheet, templateFile string) ([]byte, error) {
+	tmplSrc, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data := NewTemplateData(dateStart, dateEnd, timeSheets)
+	name := filepath.Base(templateFile)
+
+	var out bytes.Buffer
+
+	switch strings.ToLower(filepath.Ext(templateFile)) {
+	case ".html", ".htm":
+		tmpl, err := htmltemplate.New(name).Funcs(TemplateFuncs()).Parse(string(tmplSrc))
+		if err != nil {
+			return nil, err
+		}
+		err = tmpl.Execute(&out, data)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		tmpl, err := template.New(name).Funcs(TemplateFuncs()).Parse(string(tmplSrc))
+		if err != nil {
+			return nil, err
+		}
+		err = tmpl.Execute(&out, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,78 @@
+package report_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+)
+
+func TestRenderTemplateReport(t *testing.T) {
+	timeSheets := []peopleapi.TimeSheet{
+		{
+			TimesheetDate: "2023-03-06",
+			TimeIn1:       "09:00:00",
+			TimeOut1:      "12:00:00",
+			TimeIn2:       "13:00:00",
+			TimeOut2:      "17:30:00",
+		},
+		{
+			TimesheetDate: "2023-03-02",
+			TimeIn1:       "08:00:00",
+			TimeOut1:      "16:15:00",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		fileName string
+		template string
+		want     string
+	}{
+		{
+			name:     "days and intervals",
+			fileName: "report.tmpl",
+			template: `{{range .Days}}{{date "2006-01-02" .Date}} {{duration .Duration}}:{{range .Intervals}} {{clock .Start}}-{{clock .End}}{{end}}
+{{end}}`,
+			want: "2023-03-02 8h15m: 08:00-16:15\n2023-03-06 7h30m: 09:00-12:00 13:00-17:30\n",
+		},
+		{
+			name:     "weekly subtotals and total",
+			fileName: "report.tmpl",
+			template: `{{range .Weeks}}W{{.Week}} {{hours .Duration}} {{len .Days}}
+{{end}}{{duration .Total}}`,
+			want: "W9 8.2 1\nW10 7.5 1\n15h45m",
+		},
+		{
+			name:     "html escaping",
+			fileName: "report.html",
+			template: `<b>{{"<x>"}}</b>`,
+			want:     "<b>&lt;x&gt;</b>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), tt.fileName)
+			if err := os.WriteFile(fileName, []byte(tt.template), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := report.RenderTemplateReport(
+				time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC),
+				timeSheets,
+				fileName,
+			)
+			if err != nil {
+				t.Fatalf("RenderTemplateReport() error = %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("RenderTemplateReport() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
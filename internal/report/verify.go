@@ -0,0 +1,235 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// VerifyResult compares the time computed by wink from the timesheet
+// intervals with the total reported by PeopleHR for the same period.
+type VerifyResult struct {
+	Period   string
+	Label    string
+	Computed time.Duration
+	Server   time.Duration
+	// Partial is set when the report range does not cover the whole period,
+	// so the computed time can't be complete
+	Partial bool
+}
+
+// Diff returns the computed time minus the server time.
+func (v VerifyResult) Diff() time.Duration {
+	return v.Computed - v.Server
+}
+
+// IsMismatch reports whether the difference exceeds the tolerance.
+// Partial periods are never considered a mismatch.
+func (v VerifyResult) IsMismatch(tolerance time.Duration) bool {
+	diff := v.Diff()
+	if diff < 0 {
+		diff = -diff
+	}
+	return !v.Partial && diff > tolerance
+}
+
+type VerifyResultJSON struct {
+	Period          string `json:"period"`
+	Label           string `json:"label"`
+	ComputedMinutes int    `json:"computed_minutes"`
+	ServerMinutes   int    `json:"server_minutes"`
+	Partial         bool   `json:"partial"`
+	IsMismatch      bool   `json:"is_mismatch"`
+}
+
+// VerifyTotals compares the computed daily, weekly and monthly totals
+// with the TotalTimeWorked*InMins values PeopleHR returns.
+//
+// The server week and month totals are taken from the last timesheet of
+// each period and compared with the computed time up to that date.
+func VerifyTotals(dateStart time.Time, dateEnd time.Time, timeSheets []peopleapi.TimeSheet) []VerifyResult {
+	var days, weeks, months []VerifyResult
+
+	rangeStart := time.Date(dateStart.Year(), dateStart.Month(), dateStart.Day(), 0, 0, 0, 0, time.UTC)
+
+	serverByDate := make(map[string]*peopleapi.TimeSheet)
+	for i := range timeSheets {
+		if date, err := peopleapi.ParseDate(timeSheets[i].TimesheetDate); err == nil {
+			serverByDate[date.Format("2006-01-02")] = &timeSheets[i]
+		}
+	}
+
+	var weekComputed, monthComputed time.Duration
+
+	// a timesheet whose date can't be read belongs to no period
+	totals := []TimesheetDailyTotal{}
+	for _, total := range calculateTotals(timeSheets) {
+		if total.RawDate == "" {
+			totals = append(totals, total)
+		}
+	}
+
+	for i, total := range totals {
+		key := total.Date.Format("2006-01-02")
+		timeSheet := serverByDate[key]
+
+		if server, ok := parseServerMinutes(timeSheet.TotalTimeWorkedTodayInMins); ok {
+			days = append(days, VerifyResult{
+				Period:   PeriodDay,
+				Label:    key,
+				Computed: total.Duration,
+				Server:   server,
+			})
+		}
+
+		year, week := total.Date.ISOWeek()
+		if i > 0 {
+			prevYear, prevWeek := totals[i-1].Date.ISOWeek()
+			if prevYear != year || prevWeek != week {
+				weekComputed = 0
+			}
+			if totals[i-1].Date.Month() != total.Date.Month() || totals[i-1].Date.Year() != total.Date.Year() {
+				monthComputed = 0
+			}
+		}
+		weekComputed += total.Duration
+		monthComputed += total.Duration
+
+		isLast := i == len(totals)-1
+
+		nextYear, nextWeek := 0, 0
+		if !isLast {
+			nextYear, nextWeek = totals[i+1].Date.ISOWeek()
+		}
+		if isLast || nextYear != year || nextWeek != week {
+			if server, ok := parseServerMinutes(timeSheet.TotalTimeWorkedThisWeekInMins); ok {
+				weekStart := total.Date.AddDate(0, 0, -((int(total.Date.Weekday()) + 6) % 7))
+				weeks = append(weeks, VerifyResult{
+					Period:   PeriodWeek,
+					Label:    fmt.Sprintf("%d-W%02d", year, week),
+					Computed: weekComputed,
+					Server:   server,
+					Partial:  weekStart.Before(rangeStart),
+				})
+			}
+		}
+
+		if isLast || totals[i+1].Date.Month() != total.Date.Month() || totals[i+1].Date.Year() != total.Date.Year() {
+			if server, ok := parseServerMinutes(timeSheet.TotalTimeWorkedThisMonthInMins); ok {
+				monthStart := time.Date(total.Date.Year(), total.Date.Month(), 1, 0, 0, 0, 0, time.UTC)
+				months = append(months, VerifyResult{
+					Period:   PeriodMonth,
+					Label:    total.Date.Format("2006-01"),
+					Computed: monthComputed,
+					Server:   server,
+					Partial:  monthStart.Before(rangeStart),
+				})
+			}
+		}
+	}
+
+	results := append(days, weeks...)
+	return append(results, months...)
+}
+
+func parseServerMinutes(s string) (time.Duration, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+
+	minutes, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return time.Duration(minutes * float64(time.Minute)), true
+}
+
+func RenderVerifyReportJSON(results []VerifyResult, tolerance time.Duration) ([]byte, error) {
+	resultsJSON := []VerifyResultJSON{}
+
+	for _, result := range results {
+		resultsJSON = append(resultsJSON, VerifyResultJSON{
+			Period:          result.Period,
+			Label:           result.Label,
+			ComputedMinutes: int(result.Computed.Round(time.Minute).Minutes()),
+			ServerMinutes:   int(result.Server.Round(time.Minute).Minutes()),
+			Partial:         result.Partial,
+			IsMismatch:      result.IsMismatch(tolerance),
+		})
+	}
+
+	return json.MarshalIndent(resultsJSON, "", "  ")
+}
+
+func RenderVerifyReport(dateStart time.Time, dateEnd time.Time, results []VerifyResult, tolerance time.Duration, opts RenderOptions) string {
+	dimmed := color.New(color.Faint).SprintFunc()
+
+	var report strings.Builder
+
+	report.WriteString(dimmed("-----------------------------------------------\n"))
+
+	report.WriteString(color.CyanString("# Verification against PeopleHR totals"))
+	report.WriteString("\n")
+
+	report.WriteString(dimmed("From : "))
+	report.WriteString(dateStart.Format(opts.dateFormat()))
+	report.WriteString("\n")
+	report.WriteString(dimmed("To   : "))
+	report.WriteString(dateEnd.Format(opts.dateFormat()))
+	report.WriteString("\n")
+
+	mismatches := 0
+	period := ""
+
+	for _, result := range results {
+		if result.Period != period {
+			period = result.Period
+			report.WriteString("\n")
+			report.WriteString(dimmed(fmt.Sprintf("%-10s %9s %9s %9s\n", period, "wink", "server", "diff")))
+		}
+
+		line := fmt.Sprintf("%-10s %9s %9s %9s",
+			result.Label,
+			FormatDuration(result.Computed),
+			FormatDuration(result.Server),
+			FormatDuration(result.Diff()),
+		)
+
+		switch {
+		case result.IsMismatch(tolerance):
+			mismatches++
+			report.WriteString(color.RedString(line + "  mismatch"))
+		case result.Partial:
+			report.WriteString(dimmed(line + "  partial"))
+		default:
+			report.WriteString(line)
+		}
+		report.WriteString("\n")
+	}
+
+	report.WriteString("\n")
+	if mismatches == 0 {
+		report.WriteString(color.GreenString("No mismatches"))
+	} else {
+		report.WriteString(color.RedString(fmt.Sprintf("%d mismatch(es)", mismatches)))
+	}
+	report.WriteString("\n")
+
+	report.WriteString(dimmed("\n-----------------------------------------------\n"))
+
+	return report.String()
+}
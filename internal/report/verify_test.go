@@ -0,0 +1,70 @@
+package report_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+)
+
+func TestVerifyTotals(t *testing.T) {
+	timeSheets := []peopleapi.TimeSheet{
+		{
+			TimesheetDate:                  "2023-03-30",
+			TimeIn1:                        "09:00:00",
+			TimeOut1:                       "17:00:00",
+			TotalTimeWorkedTodayInMins:     "480",
+			TotalTimeWorkedThisWeekInMins:  "480",
+			TotalTimeWorkedThisMonthInMins: "960",
+		},
+		{
+			TimesheetDate:                  "2023-03-31",
+			TimeIn1:                        "09:00:00",
+			TimeOut1:                       "17:00:00",
+			TotalTimeWorkedTodayInMins:     "450",
+			TotalTimeWorkedThisWeekInMins:  "930",
+			TotalTimeWorkedThisMonthInMins: "1410",
+		},
+		{
+			TimesheetDate:                  "2023-04-03",
+			TimeIn1:                        "09:00:00",
+			TimeOut1:                       "12:00:00",
+			TotalTimeWorkedTodayInMins:     "",
+			TotalTimeWorkedThisWeekInMins:  "180",
+			TotalTimeWorkedThisMonthInMins: "180",
+		},
+	}
+
+	got := report.VerifyTotals(
+		time.Date(2023, 3, 27, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 4, 4, 0, 0, 0, 0, time.UTC),
+		timeSheets,
+	)
+
+	want := []report.VerifyResult{
+		{Period: report.PeriodDay, Label: "2023-03-30", Computed: 8 * time.Hour, Server: 8 * time.Hour},
+		{Period: report.PeriodDay, Label: "2023-03-31", Computed: 8 * time.Hour, Server: 450 * time.Minute},
+		{Period: report.PeriodWeek, Label: "2023-W13", Computed: 16 * time.Hour, Server: 930 * time.Minute},
+		{Period: report.PeriodWeek, Label: "2023-W14", Computed: 3 * time.Hour, Server: 3 * time.Hour},
+		{Period: report.PeriodMonth, Label: "2023-03", Computed: 16 * time.Hour, Server: 1410 * time.Minute, Partial: true},
+		{Period: report.PeriodMonth, Label: "2023-04", Computed: 3 * time.Hour, Server: 3 * time.Hour},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("VerifyTotals() got = %v, want %v", got, want)
+	}
+
+	var mismatches []string
+	for _, result := range got {
+		if result.IsMismatch(time.Minute) {
+			mismatches = append(mismatches, result.Label)
+		}
+	}
+
+	wantMismatches := []string{"2023-03-31", "2023-W13"}
+	if !reflect.DeepEqual(mismatches, wantMismatches) {
+		t.Errorf("IsMismatch() got = %v, want %v", mismatches, wantMismatches)
+	}
+}
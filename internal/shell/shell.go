@@ -0,0 +1,14 @@
+package shell
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// Command returns a command running the snippet through the system shell
+func Command(snippet string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", snippet)
+	}
+	return exec.Command("sh", "-c", snippet)
+}
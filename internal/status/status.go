@@ -0,0 +1,188 @@
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/report"
+)
+
+const (
+	OutputPlain    = "plain"
+	OutputWaybar   = "waybar"
+	OutputI3blocks = "i3blocks"
+	OutputTmux     = "tmux"
+)
+
+// DefaultFormat renders e.g. "●IN 3h12m" or "○OUT"
+const DefaultFormat = "{{.Icon}}{{.State}}{{if .In}} {{.Total}}{{end}}"
+
+// Status is the check-in state of today, as far as it is known locally
+type Status struct {
+	// Known is false when today's timesheet is not available
+	Known bool
+	In    bool
+	// Worked is the time worked today up to now
+	Worked time.Duration
+	// Since is the time of the last check-in or check-out, empty if there is none
+	Since string
+	// Stale is set when the timesheet it is made of may be out of date
+	Stale bool
+}
+
+// FromTimeSheet returns the status of today's timesheet, nil if there is none yet
+func FromTimeSheet(timeSheet *peopleapi.TimeSheet, now time.Time) Status {
+	return NewSnapshot(timeSheet).Status(now)
+}
+
+// Snapshot is the part of today's timesheet a Status is made of,
+// so that it can be kept without the timesheet itself
+type Snapshot struct {
+	In bool
+	// OpenIn is the check-in of the open interval as the time since midnight, zero if checked out
+	OpenIn time.Duration
+	// Worked is the time of the closed intervals
+	Worked time.Duration
+	// Since is the time of the last check-in or check-out, empty if there is none
+	Since string
+}
+
+// NewSnapshot returns the snapshot of today's timesheet, nil if there is none yet
+func NewSnapshot(timeSheet *peopleapi.TimeSheet) Snapshot {
+	var snapshot Snapshot
+	if timeSheet == nil {
+		return snapshot
+	}
+
+	total, err := report.CalculateHours(timeSheet)
+	if err != nil {
+		return snapshot
+	}
+
+	since, checkedIn := total.CheckedInSince()
+
+	snapshot.In = checkedIn
+	snapshot.Worked = total.Duration
+	if checkedIn {
+		snapshot.OpenIn = since.Sub(total.Date)
+	} else {
+		since = total.LastOut
+	}
+	if !since.IsZero() {
+		snapshot.Since = since.Format("15:04")
+	}
+
+	return snapshot
+}
+
+// Status returns the status at now, counting the open interval up to the clock time of now
+func (s Snapshot) Status(now time.Time) Status {
+	status := Status{Known: true, In: s.In, Worked: s.Worked, Since: s.Since}
+
+	if s.In {
+		clock := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute + time.Duration(now.Second())*time.Second
+		if open := clock - s.OpenIn; open > 0 {
+			status.Worked += open
+		}
+	}
+
+	return status
+}
+
+// Fields are what the format template can use
+type Fields struct {
+	Icon  string
+	State string
+	In    bool
+	Total string
+	Since string
+	Stale bool
+}
+
+func (s Status) fields() Fields {
+	f := Fields{
+		Icon:  "",
+		State: "?",
+		In:    s.In,
+		Total: report.FormatDuration(s.Worked),
+		Since: s.Since,
+		Stale: s.Stale,
+	}
+
+	switch {
+	case !s.Known:
+	case s.In:
+		f.Icon, f.State = "●", "IN"
+	default:
+		f.Icon, f.State = "○", "OUT"
+	}
+
+	return f
+}
+
+func (s Status) class() string {
+	switch {
+	case !s.Known:
+		return "unknown"
+	case s.In:
+		return "in"
+	}
+	return "out"
+}
+
+// Render formats the status with the text/template format
+// for a shell prompt, waybar, i3blocks or tmux
+func Render(s Status, format string, output string) (string, error) {
+	tmpl, err := template.New("status").Parse(format)
+	if err != nil {
+		return "", err
+	}
+
+	fields := s.fields()
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, fields); err != nil {
+		return "", err
+	}
+	text := buf.String()
+
+	tooltip := "today's timesheet is not cached"
+	if s.Known {
+		tooltip = fmt.Sprintf("%s since %s, %s today", fields.State, fields.Since, fields.Total)
+		if fields.Since == "" {
+			tooltip = "not checked in today"
+		}
+	}
+
+	switch output {
+	case OutputPlain:
+		return text, nil
+	case OutputWaybar:
+		data, err := json.Marshal(map[string]string{
+			"text":    text,
+			"tooltip": tooltip,
+			"class":   s.class(),
+			"alt":     s.class(),
+		})
+		return string(data), err
+	case OutputI3blocks:
+		data, err := json.Marshal(map[string]string{
+			"full_text":  text,
+			"short_text": fields.Icon,
+			"color":      map[bool]string{true: "#00ff00", false: "#888888"}[s.Known && s.In],
+		})
+		return string(data), err
+	case OutputTmux:
+		color := "colour244"
+		if s.Known && s.In {
+			color = "green"
+		}
+		return fmt.Sprintf("#[fg=%s]%s#[default]", color, text), nil
+	}
+
+	return "", fmt.Errorf("unknown output %q, use %s, %s, %s or %s", output, OutputPlain, OutputWaybar, OutputI3blocks, OutputTmux)
+}
@@ -0,0 +1,72 @@
+package status_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/status"
+)
+
+func TestRender(t *testing.T) {
+	now := time.Date(2023, 5, 2, 12, 12, 0, 0, time.UTC)
+
+	checkedIn := status.FromTimeSheet(&peopleapi.TimeSheet{TimesheetDate: "2023-05-02", TimeIn1: "09:00:00"}, now)
+	checkedOut := status.FromTimeSheet(&peopleapi.TimeSheet{TimesheetDate: "2023-05-02", TimeIn1: "09:00:00", TimeOut1: "11:00:00"}, now)
+	noCheckIn := status.FromTimeSheet(nil, now)
+
+	tests := []struct {
+		name    string
+		status  status.Status
+		format  string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "checked in", status: checkedIn, format: status.DefaultFormat, output: status.OutputPlain, want: "●IN 3h12m"},
+		{name: "checked out", status: checkedOut, format: status.DefaultFormat, output: status.OutputPlain, want: "○OUT"},
+		{name: "no check-in", status: noCheckIn, format: status.DefaultFormat, output: status.OutputPlain, want: "○OUT"},
+		{name: "unknown", status: status.Status{}, format: status.DefaultFormat, output: status.OutputPlain, want: "?"},
+		{name: "custom format", status: checkedOut, format: "{{.State}} {{.Total}} since {{.Since}}", output: status.OutputPlain, want: "OUT 2h00m since 11:00"},
+		{name: "tmux", status: checkedIn, format: status.DefaultFormat, output: status.OutputTmux, want: "#[fg=green]●IN 3h12m#[default]"},
+		{
+			name:   "waybar",
+			status: checkedIn, format: status.DefaultFormat, output: status.OutputWaybar,
+			want: `{"alt":"in","class":"in","text":"●IN 3h12m","tooltip":"IN since 09:00, 3h12m today"}`,
+		},
+		{
+			name:   "i3blocks",
+			status: checkedOut, format: status.DefaultFormat, output: status.OutputI3blocks,
+			want: `{"color":"#888888","full_text":"○OUT","short_text":"○"}`,
+		},
+		{name: "unknown output", status: checkedIn, format: status.DefaultFormat, output: "xmobar", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := status.Render(tt.status, tt.format, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotStatus(t *testing.T) {
+	timeSheet := &peopleapi.TimeSheet{TimesheetDate: "2023-05-02", TimeIn1: "08:00:00", TimeOut1: "09:00:00", TimeIn2: "10:00:00"}
+
+	snapshot := status.NewSnapshot(timeSheet)
+
+	want := status.Snapshot{In: true, OpenIn: 10 * time.Hour, Worked: time.Hour, Since: "10:00"}
+	if snapshot != want {
+		t.Fatalf("NewSnapshot() = %+v, want %+v", snapshot, want)
+	}
+
+	// the open interval counts up to the time the snapshot is read at
+	now := time.Date(2023, 5, 2, 12, 30, 0, 0, time.UTC)
+	if got := snapshot.Status(now).Worked; got != 3*time.Hour+30*time.Minute {
+		t.Errorf("Status().Worked = %s, want 3h30m", got)
+	}
+}
@@ -7,11 +7,7 @@ import (
 	"github.com/beevik/ntp"
 )
 
-const (
-	ntpServer = "pool.ntp.org"
-)
-
-func GetTimeDifference() (time.Duration, error) {
+func GetTimeDifference(ntpServer string) (time.Duration, error) {
 	ntpTime, err := ntp.Time(ntpServer)
 	if err != nil {
 		return 0, err
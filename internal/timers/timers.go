@@ -0,0 +1,201 @@
+package timers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	RemindUnit   = "wink-remind"
+	CheckoutUnit = "wink-checkout"
+)
+
+// Options describe the units to generate
+type Options struct {
+	// Executable is the absolute path of the wink binary
+	Executable string
+	// Env is passed to the services, e.g. WINK_HOME
+	Env []string
+	// RemindInterval is how often `wink remind` runs, 0 for no reminder timer
+	RemindInterval time.Duration
+	// AutoCheckout is the time since midnight of the automatic check-out, 0 for none
+	AutoCheckout time.Duration
+	// Workdays are the days the automatic check-out runs on
+	Workdays []time.Weekday
+}
+
+// Unit is a systemd unit file
+type Unit struct {
+	Name    string
+	Content string
+}
+
+// Names returns the file names of every unit wink may generate
+func Names() []string {
+	var names []string
+	for _, unit := range []string{RemindUnit, CheckoutUnit} {
+		names = append(names, unit+".service", unit+".timer")
+	}
+	return names
+}
+
+// Units returns the service and timer units for the options
+func Units(opts Options) []Unit {
+	var units []Unit
+
+	if opts.RemindInterval > 0 {
+		units = append(units,
+			Unit{
+				Name:    RemindUnit + ".service",
+				Content: service("Remind about wink check-ins and check-outs", opts, "remind"),
+			},
+			Unit{
+				Name: RemindUnit + ".timer",
+				Content: timer("Run wink remind periodically",
+					"OnBootSec=1min",
+					fmt.Sprintf("OnUnitActiveSec=%ds", int(opts.RemindInterval.Seconds())),
+				),
+			},
+		)
+	}
+
+	// an unattended check-out never closes a shift left open on another day
+	if opts.AutoCheckout > 0 {
+		units = append(units,
+			Unit{
+				Name:    CheckoutUnit + ".service",
+				Content: service("Check out of work with wink", opts, "out --no-overnight"),
+			},
+			Unit{
+				Name: CheckoutUnit + ".timer",
+				Content: timer("Check out of work with wink at the end of the day",
+					"OnCalendar="+onCalendar(opts.Workdays, opts.AutoCheckout),
+				),
+			},
+		)
+	}
+
+	return units
+}
+
+func service(description string, opts Options, args string) string {
+	var b strings.Builder
+
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=" + description + "\n")
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=oneshot\n")
+	for _, env := range opts.Env {
+		b.WriteString("Environment=" + quote(env) + "\n")
+	}
+	// $ starts a variable on the command line of a service, $$ is a literal $
+	b.WriteString("ExecStart=" + strings.ReplaceAll(quote(opts.Executable), "$", "$$") + " " + args + "\n")
+
+	return b.String()
+}
+
+// quote double-quotes a value for a unit file as described in systemd.syntax(7):
+// backslashes, quotes and control characters are escaped C-style, and % is doubled as it starts a specifier
+func quote(s string) string {
+	var b strings.Builder
+
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '"':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '%':
+			b.WriteString("%%")
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+func timer(description string, schedule ...string) string {
+	var b strings.Builder
+
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=" + description + "\n")
+	b.WriteString("\n[Timer]\n")
+	for _, line := range schedule {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=timers.target\n")
+
+	return b.String()
+}
+
+// onCalendar formats a systemd calendar event, e.g. "Mon,Fri *-*-* 19:00:00"
+func onCalendar(days []time.Weekday, at time.Duration) string {
+	var names []string
+	for _, day := range days {
+		names = append(names, day.String()[:3])
+	}
+
+	clock := fmt.Sprintf("*-*-* %02d:%02d:00", int(at.Hours()), int(at.Minutes())%60)
+	if len(names) == 0 {
+		return clock
+	}
+	return strings.Join(names, ",") + " " + clock
+}
+
+// Dir returns the directory of systemd user units
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "systemd", "user"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+// Write writes the units to the directory, replacing the existing ones
+func Write(dir string, units []Unit) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, unit := range units {
+		if err := os.WriteFile(filepath.Join(dir, unit.Name), []byte(unit.Content), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove deletes every unit wink may have written, returning the removed file names
+func Remove(dir string) ([]string, error) {
+	var removed []string
+
+	for _, name := range Names() {
+		err := os.Remove(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return removed, err
+		}
+		removed = append(removed, name)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,80 @@
+package timers_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/timers"
+)
+
+func TestUnits(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      timers.Options
+		wantNames []string
+		wantLines []string
+	}{
+		{
+			name: "reminders only",
+			opts: timers.Options{
+				Executable:     "/usr/local/bin/wink",
+				Env:            []string{"WINK_HOME=/home/me/.wink"},
+				RemindInterval: 5 * time.Minute,
+			},
+			wantNames: []string{"wink-remind.service", "wink-remind.timer"},
+			wantLines: []string{
+				`ExecStart="/usr/local/bin/wink" remind`,
+				`Environment="WINK_HOME=/home/me/.wink"`,
+				"OnUnitActiveSec=300s",
+			},
+		},
+		{
+			name: "auto check-out on workdays",
+			opts: timers.Options{
+				Executable:   "/usr/local/bin/wink",
+				AutoCheckout: 19*time.Hour + 30*time.Minute,
+				Workdays:     []time.Weekday{time.Monday, time.Friday},
+			},
+			wantNames: []string{"wink-checkout.service", "wink-checkout.timer"},
+			wantLines: []string{
+				`ExecStart="/usr/local/bin/wink" out --no-overnight`,
+				"OnCalendar=Mon,Fri *-*-* 19:30:00",
+			},
+		},
+		{
+			name: "paths needing quotes",
+			opts: timers.Options{
+				Executable:     `/home/me/my "bin"/100%/$wink`,
+				Env:            []string{`WINK_HOME=C:\wink 50%`},
+				RemindInterval: time.Minute,
+			},
+			wantNames: []string{"wink-remind.service", "wink-remind.timer"},
+			wantLines: []string{
+				`ExecStart="/home/me/my \"bin\"/100%%/$$wink" remind`,
+				`Environment="WINK_HOME=C:\\wink 50%%"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			units := timers.Units(tt.opts)
+
+			var names []string
+			var content strings.Builder
+			for _, unit := range units {
+				names = append(names, unit.Name)
+				content.WriteString(unit.Content)
+			}
+
+			if strings.Join(names, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("Units() names = %v, want %v", names, tt.wantNames)
+			}
+			for _, line := range tt.wantLines {
+				if !strings.Contains(content.String(), line+"\n") {
+					t.Errorf("Units() has no line %q:\n%s", line, content.String())
+				}
+			}
+		})
+	}
+}
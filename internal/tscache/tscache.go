@@ -0,0 +1,258 @@
+package tscache
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/harnyk/wink/internal/cryptostore"
+	"github.com/harnyk/wink/internal/peopleapi"
+)
+
+const (
+	TTLToday       = time.Duration(5 * time.Minute)
+	TTLCurrentWeek = time.Duration(1 * time.Hour)
+	TTLCurrentMon  = time.Duration(24 * time.Hour)
+	TTLPastMonths  = time.Duration(30 * 24 * time.Hour)
+	// TTLHolidays is how long the holiday dates stay fresh, holidays get approved for any date
+	TTLHolidays = time.Duration(24 * time.Hour)
+)
+
+// Entry is the cached state of a single date. A nil TimeSheet means
+// there was no timesheet on that date when it was fetched.
+type Entry struct {
+	TimeSheet *peopleapi.TimeSheet
+	FetchedAt time.Time
+}
+
+// HolidayEntry is whether a date was an approved holiday when it was fetched
+type HolidayEntry struct {
+	Holiday   bool
+	FetchedAt time.Time
+}
+
+type cacheData struct {
+	EmployeeID string
+	Entries    map[string]Entry
+	Holidays   map[string]HolidayEntry
+}
+
+// Stats describe the content of the cache
+type Stats struct {
+	FileName   string
+	FileSize   int64
+	Dates      int
+	TimeSheets int
+	Stale      int
+	Oldest     time.Time
+	Newest     time.Time
+}
+
+// Cache is a local encrypted cache of fetched timesheets, keyed by date
+type Cache interface {
+	// Lookup returns the cached timesheets of the range, and the dates
+	// which are missing or have expired. With ignoreTTL, expired entries
+	// are returned as well and only missing dates are reported.
+	Lookup(startDate time.Time, endDate time.Time, now time.Time, ignoreTTL bool) ([]peopleapi.TimeSheet, []time.Time)
+	// Store saves the timesheets fetched for the range. Dates of the range
+	// without a timesheet are stored as empty.
+	Store(startDate time.Time, endDate time.Time, timeSheets []peopleapi.TimeSheet, now time.Time) error
+	// Invalidate drops the entry of a single date
+	Invalidate(date time.Time) error
+	// LookupHolidays returns the approved holiday dates of the range, and the dates
+	// whose holiday state is missing or has expired
+	LookupHolidays(startDate time.Time, endDate time.Time, now time.Time, ignoreTTL bool) (map[string]bool, []time.Time)
+	// StoreHolidays saves the approved holiday dates fetched for the range
+	StoreHolidays(startDate time.Time, endDate time.Time, holidays map[string]bool, now time.Time) error
+	Stats(now time.Time) (Stats, error)
+	Clear() error
+}
+
+func NewCache(fileName string, key string, employeeID string) Cache {
+	return &cache{
+		fileName:   fileName,
+		key:        key,
+		employeeID: employeeID,
+		store:      cryptostore.NewCryptoStore[cacheData](fileName),
+	}
+}
+
+type cache struct {
+	fileName   string
+	key        string
+	employeeID string
+	store      cryptostore.CryproStore[cacheData]
+}
+
+// TTL returns how long the entry of the date stays fresh:
+// past months rarely change, today changes all the time.
+func TTL(date time.Time, now time.Time) time.Duration {
+	y1, m1, d1 := date.Date()
+	y2, m2, d2 := now.Date()
+
+	if y1 == y2 && m1 == m2 && d1 == d2 {
+		return TTLToday
+	}
+
+	wy1, w1 := date.ISOWeek()
+	wy2, w2 := now.ISOWeek()
+	if wy1 == wy2 && w1 == w2 {
+		return TTLCurrentWeek
+	}
+
+	if y1 == y2 && m1 == m2 {
+		return TTLCurrentMon
+	}
+
+	return TTLPastMonths
+}
+
+// day strips the clock time of the date
+func day(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func dateKey(date time.Time) string {
+	return date.Format("2006-01-02")
+}
+
+// load reads the cache, an unreadable cache (missing, written with another
+// password or for another employee) is treated as empty
+func (c *cache) load() *cacheData {
+	data, err := c.store.Load(c.key)
+	if err != nil || data.EmployeeID != c.employeeID || data.Entries == nil {
+		return &cacheData{
+			EmployeeID: c.employeeID,
+			Entries:    make(map[string]Entry),
+			Holidays:   make(map[string]HolidayEntry),
+		}
+	}
+	// caches written before holidays were cached have none
+	if data.Holidays == nil {
+		data.Holidays = make(map[string]HolidayEntry)
+	}
+	return data
+}
+
+func (c *cache) Lookup(startDate time.Time, endDate time.Time, now time.Time, ignoreTTL bool) ([]peopleapi.TimeSheet, []time.Time) {
+	data := c.load()
+
+	var timeSheets []peopleapi.TimeSheet
+	var missing []time.Time
+
+	for date := day(startDate); !date.After(day(endDate)); date = date.AddDate(0, 0, 1) {
+		entry, ok := data.Entries[dateKey(date)]
+		if !ok || (!ignoreTTL && now.Sub(entry.FetchedAt) > TTL(date, now)) {
+			missing = append(missing, date)
+			continue
+		}
+
+		if entry.TimeSheet != nil {
+			timeSheets = append(timeSheets, *entry.TimeSheet)
+		}
+	}
+
+	return timeSheets, missing
+}
+
+func (c *cache) Store(startDate time.Time, endDate time.Time, timeSheets []peopleapi.TimeSheet, now time.Time) error {
+	data := c.load()
+
+	for date := day(startDate); !date.After(day(endDate)); date = date.AddDate(0, 0, 1) {
+		data.Entries[dateKey(date)] = Entry{FetchedAt: now}
+	}
+
+	for i := range timeSheets {
+		timeSheet := timeSheets[i]
+		data.Entries[timeSheet.TimesheetDate] = Entry{TimeSheet: &timeSheet, FetchedAt: now}
+	}
+
+	return c.store.Store(*data, c.key)
+}
+
+func (c *cache) LookupHolidays(startDate time.Time, endDate time.Time, now time.Time, ignoreTTL bool) (map[string]bool, []time.Time) {
+	data := c.load()
+
+	holidays := make(map[string]bool)
+	var missing []time.Time
+
+	for date := day(startDate); !date.After(day(endDate)); date = date.AddDate(0, 0, 1) {
+		entry, ok := data.Holidays[dateKey(date)]
+		if !ok || (!ignoreTTL && now.Sub(entry.FetchedAt) > TTLHolidays) {
+			missing = append(missing, date)
+			continue
+		}
+
+		if entry.Holiday {
+			holidays[dateKey(date)] = true
+		}
+	}
+
+	return holidays, missing
+}
+
+func (c *cache) StoreHolidays(startDate time.Time, endDate time.Time, holidays map[string]bool, now time.Time) error {
+	data := c.load()
+
+	for date := day(startDate); !date.After(day(endDate)); date = date.AddDate(0, 0, 1) {
+		data.Holidays[dateKey(date)] = HolidayEntry{Holiday: holidays[dateKey(date)], FetchedAt: now}
+	}
+
+	return c.store.Store(*data, c.key)
+}
+
+func (c *cache) Invalidate(date time.Time) error {
+	data := c.load()
+
+	if _, ok := data.Entries[dateKey(date)]; !ok {
+		return nil
+	}
+
+	delete(data.Entries, dateKey(date))
+
+	return c.store.Store(*data, c.key)
+}
+
+func (c *cache) Stats(now time.Time) (Stats, error) {
+	stats := Stats{FileName: c.fileName}
+
+	info, err := os.Stat(c.fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, err
+	}
+	stats.FileSize = info.Size()
+
+	for key, entry := range c.load().Entries {
+		date, err := time.Parse("2006-01-02", key)
+		if err != nil {
+			continue
+		}
+
+		stats.Dates++
+		if entry.TimeSheet != nil {
+			stats.TimeSheets++
+		}
+		if now.Sub(entry.FetchedAt) > TTL(date, now) {
+			stats.Stale++
+		}
+		if stats.Oldest.IsZero() || date.Before(stats.Oldest) {
+			stats.Oldest = date
+		}
+		if date.After(stats.Newest) {
+			stats.Newest = date
+		}
+	}
+
+	return stats, nil
+}
+
+func (c *cache) Clear() error {
+	err := os.Remove(c.fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
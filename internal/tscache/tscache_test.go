@@ -0,0 +1,110 @@
+package tscache_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/harnyk/wink/internal/peopleapi"
+	"github.com/harnyk/wink/internal/tscache"
+)
+
+func mustDate(s string) time.Time {
+	t, _ := time.Parse("2006-01-02 15:04", s)
+	return t
+}
+
+func TestTTL(t *testing.T) {
+	now := mustDate("2023-03-15 12:00") // Wednesday
+
+	tests := []struct {
+		name string
+		date time.Time
+		want time.Duration
+	}{
+		{name: "today", date: mustDate("2023-03-15 00:00"), want: tscache.TTLToday},
+		{name: "current week", date: mustDate("2023-03-13 00:00"), want: tscache.TTLCurrentWeek},
+		{name: "current month", date: mustDate("2023-03-01 00:00"), want: tscache.TTLCurrentMon},
+		{name: "past month", date: mustDate("2023-02-28 00:00"), want: tscache.TTLPastMonths},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tscache.TTL(tt.date, now); got != tt.want {
+				t.Errorf("TTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "cache")
+	cache := tscache.NewCache(fileName, "password", "42")
+
+	fetchedAt := mustDate("2023-03-15 12:00")
+	timeSheets := []peopleapi.TimeSheet{
+		{TimesheetDate: "2023-02-27", TimeIn1: "09:00:00"},
+		{TimesheetDate: "2023-03-15", TimeIn1: "10:00:00"},
+	}
+
+	err := cache.Store(mustDate("2023-02-27 00:00"), mustDate("2023-03-15 00:00"), timeSheets, fetchedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an hour later today has expired, the rest of the range is still fresh
+	got, missing := cache.Lookup(mustDate("2023-02-26 00:00"), mustDate("2023-03-15 00:00"), fetchedAt.Add(time.Hour), false)
+
+	if !reflect.DeepEqual(got, timeSheets[:1]) {
+		t.Errorf("Lookup() got = %v, want %v", got, timeSheets[:1])
+	}
+
+	wantMissing := []time.Time{mustDate("2023-02-26 00:00"), mustDate("2023-03-15 00:00")}
+	if !reflect.DeepEqual(missing, wantMissing) {
+		t.Errorf("Lookup() missing = %v, want %v", missing, wantMissing)
+	}
+
+	// offline lookups ignore the TTL
+	got, _ = cache.Lookup(mustDate("2023-03-15 00:00"), mustDate("2023-03-15 00:00"), fetchedAt.Add(time.Hour), true)
+	if !reflect.DeepEqual(got, timeSheets[1:]) {
+		t.Errorf("Lookup() ignoring TTL got = %v, want %v", got, timeSheets[1:])
+	}
+
+	// a different password can't read the cache
+	_, missing = tscache.NewCache(fileName, "other", "42").Lookup(mustDate("2023-03-15 00:00"), mustDate("2023-03-15 00:00"), fetchedAt, true)
+	if len(missing) != 1 {
+		t.Errorf("Lookup() with another password missing = %v, want 1 date", missing)
+	}
+
+	// holidays are kept apart from the timesheets
+	holidays := map[string]bool{"2023-03-01": true, "2023-04-01": true}
+	if err := cache.StoreHolidays(mustDate("2023-02-27 00:00"), mustDate("2023-03-15 00:00"), holidays, fetchedAt); err != nil {
+		t.Fatal(err)
+	}
+
+	gotHolidays, missing := cache.LookupHolidays(mustDate("2023-02-28 00:00"), mustDate("2023-03-16 00:00"), fetchedAt.Add(time.Hour), false)
+	if !reflect.DeepEqual(gotHolidays, map[string]bool{"2023-03-01": true}) {
+		t.Errorf("LookupHolidays() = %v, want 2023-03-01 only", gotHolidays)
+	}
+	if !reflect.DeepEqual(missing, []time.Time{mustDate("2023-03-16 00:00")}) {
+		t.Errorf("LookupHolidays() missing = %v, want 2023-03-16", missing)
+	}
+	if _, missing = cache.LookupHolidays(mustDate("2023-03-01 00:00"), mustDate("2023-03-01 00:00"), fetchedAt.Add(48*time.Hour), false); len(missing) != 1 {
+		t.Errorf("LookupHolidays() two days later missing = %v, want 1 date", missing)
+	}
+
+	stats, err := cache.Stats(fetchedAt.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Dates != 17 || stats.TimeSheets != 2 || stats.Stale != 1 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, missing = cache.Lookup(mustDate("2023-03-15 00:00"), mustDate("2023-03-15 00:00"), fetchedAt, true); len(missing) != 1 {
+		t.Errorf("Lookup() after Clear() missing = %v, want 1 date", missing)
+	}
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Progress prints a single-line progress indicator to stderr.
+// It does nothing when stderr is not a terminal.
+type Progress interface {
+	Update(done int, total int)
+	Done()
+}
+
+func NewProgress(label string) Progress {
+	return &progress{
+		label:   label,
+		enabled: term.IsTerminal(int(os.Stderr.Fd())),
+	}
+}
+
+type progress struct {
+	label   string
+	enabled bool
+	width   int
+}
+
+func (p *progress) Update(done int, total int) {
+	if !p.enabled {
+		return
+	}
+
+	line := fmt.Sprintf("%s %d/%d", p.label, done, total)
+	if len(line) > p.width {
+		p.width = len(line)
+	}
+
+	fmt.Fprintf(os.Stderr, "\r%s", line)
+}
+
+func (p *progress) Done() {
+	if !p.enabled || p.width == 0 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", p.width))
+}
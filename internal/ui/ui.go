@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"golang.org/x/term"
 )
@@ -10,6 +11,8 @@ import (
 type UI interface {
 	AskString(prompt string) (string, error)
 	AskPassword(prompt string) (string, error)
+	// Confirm asks a yes/no question, anything but y or yes is a no
+	Confirm(prompt string) (bool, error)
 }
 
 func NewUI() UI {
@@ -37,3 +40,15 @@ func (u *ui) AskPassword(prompt string) (string, error) {
 
 	return string(password), nil
 }
+
+func (u *ui) Confirm(prompt string) (bool, error) {
+	fmt.Printf("%s [y/N] ", prompt)
+	var result string
+	fmt.Scanln(&result)
+
+	switch strings.ToLower(strings.TrimSpace(result)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}